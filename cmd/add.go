@@ -0,0 +1,218 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kenstir/tortle/internal/bencode"
+)
+
+// addPollInterval is how often to check whether an added torrent has shown up
+var addPollInterval = time.Second
+
+type AddOptions struct {
+	SavePath   string
+	Category   string // category for qbit, label for deluge
+	Tags       string // qbit only
+	Paused     bool
+	SkipCheck  bool
+	Reannounce bool
+	Watch      string
+	Interval   int // seconds between scans of the watch dir
+	Once       bool
+}
+
+// addSource is a torrent to be added, from a .torrent file or a magnet URI
+type addSource struct {
+	Path   string // file it came from, empty for a magnet URI on the command line
+	Magnet string // set for magnets
+	Data   []byte // set for .torrent files
+	Hash   string // v1 info-hash in lowercase hex
+}
+
+// name is how the source appears in log messages
+func (s addSource) name() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return s.Hash
+}
+
+// loadAddSource loads a .torrent file, a .magnet file holding a magnet URI, or a magnet URI
+func loadAddSource(arg string) (addSource, error) {
+	if strings.HasPrefix(arg, "magnet:") {
+		hash, err := magnetInfoHash(arg)
+		return addSource{Magnet: arg, Hash: hash}, err
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return addSource{}, err
+	}
+	if strings.EqualFold(filepath.Ext(arg), ".magnet") {
+		magnet := strings.TrimSpace(string(data))
+		hash, err := magnetInfoHash(magnet)
+		return addSource{Path: arg, Magnet: magnet, Hash: hash}, err
+	}
+	hash, err := torrentInfoHash(data)
+	return addSource{Path: arg, Data: data, Hash: hash}, err
+}
+
+// torrentInfoHash returns the v1 info-hash of a .torrent file
+func torrentInfoHash(data []byte) (string, error) {
+	info, err := bencode.RawDictValue(data, "info")
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(info)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// magnetInfoHash returns the v1 info-hash from the xt=urn:btih: parameter of a magnet URI
+func magnetInfoHash(magnet string) (string, error) {
+	u, err := url.Parse(magnet)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "magnet" {
+		return "", fmt.Errorf("not a magnet URI: %s", magnet)
+	}
+	for _, xt := range u.Query()["xt"] {
+		hash, ok := strings.CutPrefix(strings.ToLower(xt), "urn:btih:")
+		if !ok {
+			continue
+		}
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				return hash, nil
+			}
+		case 32:
+			if b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				return hex.EncodeToString(b), nil
+			}
+		}
+		return "", fmt.Errorf("invalid info-hash in magnet URI: %s", hash)
+	}
+	return "", fmt.Errorf("magnet URI has no btih info-hash")
+}
+
+// isWatchFile returns true if name is a file the watch dir should pick up
+func isWatchFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".torrent" || ext == ".magnet"
+}
+
+// watchDir adds every .torrent and .magnet file in dir, renaming each to *.added or *.failed
+// so it is picked up only once.  It scans every interval until ctx is done, or once if once is set.
+func watchDir(ctx context.Context, dir string, interval time.Duration, once bool, add func(addSource) error) error {
+	vLogf("watching \"%s\"\n", dir)
+	seen := map[string]watchFile{}
+	for {
+		numAdded, numFailed, err := scanWatchDir(dir, interval, seen, add)
+		if err != nil {
+			return err
+		}
+		if once {
+			if numFailed > 0 {
+				return fmt.Errorf("%d of %d torrents could not be added", numFailed, numAdded+numFailed)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchFile is what scanWatchDir saw of a file that was still being written
+type watchFile struct {
+	size    int64
+	modTime time.Time
+}
+
+// scanWatchDir adds the files in dir that are no longer being written, i.e. that have not
+// changed since the previous scan or are older than interval, and renames them to .added
+// or .failed.  Files that are still changing are remembered in seen and left in place.
+func scanWatchDir(dir string, interval time.Duration, seen map[string]watchFile, add func(addSource) error) (numAdded int, numFailed int, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	var names []string
+	pending := map[string]watchFile{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isWatchFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		current := watchFile{size: info.Size(), modTime: info.ModTime()}
+		if previous, ok := seen[entry.Name()]; !(ok && previous == current) && time.Since(current.modTime) < interval {
+			vLogf("%s: waiting for it to be written\n", filepath.Join(dir, entry.Name()))
+			pending[entry.Name()] = current
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	clear(seen)
+	maps.Copy(seen, pending)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		src, err := loadAddSource(path)
+		if err == nil {
+			err = add(src)
+		}
+		suffix := ".added"
+		if err != nil {
+			logErrorf("%s: %v\n", path, err)
+			suffix = ".failed"
+			numFailed++
+		} else {
+			numAdded++
+		}
+		if err := os.Rename(path, path+suffix); err != nil {
+			return numAdded, numFailed, err
+		}
+	}
+	return numAdded, numFailed, nil
+}
+
+// waitForAdded calls found up to 30 times, addPollInterval apart, until it reports that
+// an added torrent has shown up.  It returns false if the torrent never did.
+func waitForAdded(ctx context.Context, found func(ctx context.Context) (bool, error)) (bool, error) {
+	for i := range 30 {
+		if i > 0 {
+			timer := time.NewTimer(addPollInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return false, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		ok, err := found(ctx)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
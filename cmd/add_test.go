@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+// a minimal single-file torrent
+var testTorrent = []byte("d8:announce23:http://tracker/announce4:infod6:lengthi5e4:name5:a.txt12:piece lengthi16384e6:pieces20:01234567890123456789ee")
+
+func TestTorrentInfoHash(t *testing.T) {
+	hash, err := torrentInfoHash(testTorrent)
+	assert.NoError(t, err)
+	assert.Equal(t, "8694d6007ae15e276cbda435c410f6e2b6bd6f76", hash)
+
+	_, err = torrentInfoHash([]byte("d8:announce3:fooe"))
+	assert.Error(t, err)
+}
+
+func TestMagnetInfoHash(t *testing.T) {
+	hash, err := magnetInfoHash("magnet:?xt=urn:btih:C12FE1C06BBA254A9DC9F519B335AA7C1367A88A&dn=foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "c12fe1c06bba254a9dc9f519b335aa7c1367a88a", hash)
+
+	hash, err = magnetInfoHash("magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK")
+	assert.NoError(t, err)
+	assert.Equal(t, "c12fe1c06bba254a9dc9f519b335aa7c1367a88a", hash)
+
+	_, err = magnetInfoHash("magnet:?dn=foo")
+	assert.Error(t, err)
+}
+
+func TestQbitAdd_WatchOnce(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.torrent"), testTorrent, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.magnet"), []byte("magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.magnet"), []byte("not a magnet"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("AddTorrentFromMemoryCtx", ctx, testTorrent, mock.MatchedBy(func(o map[string]string) bool {
+		return o["category"] == "tv" && o["paused"] == "true"
+	})).Return(nil)
+	mockClient.On("AddTorrentFromUrlCtx", ctx, "magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a", mock.Anything).Return(nil)
+
+	err := qbitAdd(ctx, mockClient, nil, AddOptions{Watch: dir, Once: true, Category: "tv", Paused: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 3")
+
+	assert.FileExists(t, filepath.Join(dir, "a.torrent.added"))
+	assert.FileExists(t, filepath.Join(dir, "b.magnet.added"))
+	assert.FileExists(t, filepath.Join(dir, "c.magnet.failed"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+	mockClient.AssertExpectations(t)
+}
+
+func TestScanWatchDir_WaitsForPartialFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.torrent")
+	assert.NoError(t, os.WriteFile(path, testTorrent[:20], 0644))
+	seen := map[string]watchFile{}
+	var added []string
+	add := func(src addSource) error {
+		added = append(added, src.Hash)
+		return nil
+	}
+
+	// new file: wait for the next scan
+	numAdded, numFailed, err := scanWatchDir(dir, time.Hour, seen, add)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, numAdded+numFailed)
+
+	// still being written: wait again
+	assert.NoError(t, os.WriteFile(path, testTorrent, 0644))
+	numAdded, numFailed, err = scanWatchDir(dir, time.Hour, seen, add)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, numAdded+numFailed)
+	assert.FileExists(t, path)
+
+	// unchanged since the last scan
+	numAdded, numFailed, err = scanWatchDir(dir, time.Hour, seen, add)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, numAdded)
+	assert.Equal(t, 0, numFailed)
+	assert.Equal(t, []string{"8694d6007ae15e276cbda435c410f6e2b6bd6f76"}, added)
+	assert.FileExists(t, path+".added")
+}
+
+func TestWaitForAdded_Canceled(t *testing.T) {
+	saved := addPollInterval
+	addPollInterval = time.Hour
+	t.Cleanup(func() { addPollInterval = saved })
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the first check fails, then cancel instead of waiting an hour for the next one
+	calls := 0
+	found, err := waitForAdded(ctx, func(ctx context.Context) (bool, error) {
+		calls++
+		cancel()
+		return false, nil
+	})
+	assert.False(t, found)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
@@ -4,13 +4,58 @@ Copyright © 2025 Kenneth H. Cox
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// configProbe holds what tt config init found out about the local clients
+type configProbe struct {
+	DelugeFound    bool
+	DelugeServer   string
+	DelugePort     int
+	DelugeUsername string
+	DelugePassword string
+	DelugeStateDir string
+
+	QbitFound    bool
+	QbitServer   string
+	QbitUsername string
+	QbitPassword string // never found, qBittorrent.conf has only a hash
+}
+
+// sampleConfig is what tt config prints
+var sampleConfig = configProbe{
+	DelugeFound:    true,
+	DelugeServer:   "192.168.1.222",
+	DelugePort:     58846,
+	DelugeUsername: "admin",
+	DelugePassword: "password",
+	QbitFound:      true,
+	QbitServer:     "http://192.168.1.222:8080",
+	QbitUsername:   "admin",
+	QbitPassword:   "password",
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configShowCmd)
+
+	configInitCmd.Flags().StringP("output", "o", "tt.toml", "File to write, or - for stdout")
+	configInitCmd.Flags().Bool("force", false, "Overwrite an existing file")
+	viper.BindPFlag("config.init.output", configInitCmd.Flags().Lookup("output"))
+	viper.BindPFlag("config.init.force", configInitCmd.Flags().Lookup("force"))
 }
 
 var configCmd = &cobra.Command{
@@ -20,22 +65,289 @@ var configCmd = &cobra.Command{
 
 like so:
     tt config > tt.toml
+
+or generate one for the clients running on this host with:
+    tt config init
 `,
 	Run: configCmdRun,
 }
 
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a config file for the clients running on this host",
+	Long: `Write a config file for the clients running on this host.
+
+Probes localhost for the qBittorrent WebUI and the deluge daemon, and reads
+the deluge auth file and qBittorrent.conf if they are found in the usual places.
+qBittorrent stores only a hash of the WebUI password, so that has to be filled in.`,
+	Args: cobra.NoArgs,
+	Run:  configInitCmdRun,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective config, with secrets redacted",
+	Long: `Print the effective config, merged from defaults, tt.toml, TT_ environment
+variables and flags, with passwords redacted.`,
+	Args: cobra.NoArgs,
+	Run:  configShowCmdRun,
+}
+
 func configCmdRun(cmd *cobra.Command, args []string) {
-	// TODO: make this work for default localhost installations
-	fmt.Printf(`[deluge]
-server = "192.168.1.222"
-port = 58846
-username = "admin"
-password = "password"
-
-[qbit]
-server = "http://192.168.1.222:8080"
-username = "admin"
-password = "password"
+	fmt.Print(renderConfig(sampleConfig))
+}
+
+func configInitCmdRun(cmd *cobra.Command, args []string) {
+	output := viper.GetString("config.init.output")
+	force := viper.GetBool("config.init.force")
+
+	probe := probeLocalClients()
+	if !probe.DelugeFound && !probe.QbitFound {
+		logErrorf("No clients found on localhost; edit the config to point to your servers\n")
+		probe.DelugeFound, probe.QbitFound = true, true
+	}
+	if probe.DelugePassword == "" {
+		probe.DelugePassword = "password"
+	}
+	if probe.QbitFound {
+		logErrorf("Set the qbit password in the config; qBittorrent.conf has only a hash of it\n")
+	}
+	probe.QbitPassword = "password"
+	content := renderConfig(probe)
+
+	if output == "-" {
+		fmt.Print(content)
+		return
+	}
+	if _, err := os.Stat(output); err == nil && !force {
+		fatalError(fmt.Errorf("%s already exists; use --force to overwrite it", output))
+	}
+	err := os.WriteFile(output, []byte(content), 0600)
+	if err != nil {
+		fatalError(err)
+	}
+	logf("Wrote %s\n", output)
+}
+
+func configShowCmdRun(cmd *cobra.Command, args []string) {
+	out, err := toml.Marshal(redactSettings(viper.AllSettings()))
+	if err != nil {
+		fatalError(err)
+	}
+	fmt.Print(string(out))
+}
+
+// renderConfig returns a config file for the probed clients, with the sections
+// for clients that were not found commented out
+func renderConfig(p configProbe) string {
+	var b strings.Builder
+	deluge := fmt.Sprintf(`[deluge]
+server = %q
+port = %d
+username = %q
+password = %q
+`, p.DelugeServer, p.DelugePort, p.DelugeUsername, p.DelugePassword)
+	if p.DelugeStateDir != "" {
+		deluge += fmt.Sprintf("state-dir = %q\n", p.DelugeStateDir)
+	} else {
+		deluge += "#state-dir = \"/var/lib/deluge/.config/deluge/state\"\n"
+	}
+	b.WriteString(commentOutUnless(p.DelugeFound, deluge))
+	b.WriteString("\n")
+
+	qbit := fmt.Sprintf(`[qbit]
+server = %q
+username = %q
+password = %q
+`, p.QbitServer, p.QbitUsername, p.QbitPassword)
+	b.WriteString(commentOutUnless(p.QbitFound, qbit))
+	b.WriteString(`# or read the password from a file, e.g. a Docker secret, or set $TT_QBIT_PASSWORD
+#password_file = "/run/secrets/qbit"
 #columns = ["ratio","hash","name","save_path"]
+
+# more servers, selected with --instance seedbox1, or --instance all for ls and stats;
+# settings not given here are taken from [qbit]
+#[qbit.seedbox1]
+#server = "http://192.168.1.223:8080"
+
+# map paths reported by the clients (e.g. inside Docker) to paths on this host
+#[pathmap]
+#prefixes = ["/downloads=/mnt/pool/downloads"]
 `)
+	return b.String()
+}
+
+func commentOutUnless(found bool, section string) string {
+	if found {
+		return section
+	}
+	lines := strings.SplitAfter(section, "\n")
+	for i, line := range lines {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// probeLocalClients looks for a deluge daemon and a qBittorrent WebUI on localhost
+func probeLocalClients() configProbe {
+	p := configProbe{
+		DelugeServer:   "localhost",
+		DelugePort:     58846,
+		DelugeUsername: "localclient",
+		QbitServer:     "http://localhost:8080",
+		QbitUsername:   "admin",
+	}
+
+	// deluge
+	for _, dir := range delugeConfigDirs() {
+		if data, err := os.ReadFile(filepath.Join(dir, "core.conf")); err == nil {
+			if port, ok := parseDelugeCoreConf(string(data)); ok {
+				p.DelugePort = port
+			}
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "auth"))
+		if err != nil {
+			continue
+		}
+		vLogf("Found deluge auth file in %s\n", dir)
+		if username, password, ok := parseDelugeAuth(string(data)); ok {
+			p.DelugeUsername, p.DelugePassword = username, password
+		}
+		if fi, err := os.Stat(filepath.Join(dir, "state")); err == nil && fi.IsDir() {
+			p.DelugeStateDir = filepath.ToSlash(filepath.Join(dir, "state"))
+		}
+		break
+	}
+	p.DelugeFound = probePort("localhost", p.DelugePort)
+	vLogf("deluge daemon on port %d: %v\n", p.DelugePort, p.DelugeFound)
+
+	// qbit
+	port := 8080
+	for _, file := range qbitConfigFiles() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		vLogf("Found %s\n", file)
+		confPort, username := parseQbitConf(string(data))
+		if confPort > 0 {
+			port = confPort
+		}
+		if username != "" {
+			p.QbitUsername = username
+		}
+		break
+	}
+	p.QbitServer = fmt.Sprintf("http://localhost:%d", port)
+	p.QbitFound = probePort("localhost", port)
+	vLogf("qbit WebUI on port %d: %v\n", port, p.QbitFound)
+
+	return p
+}
+
+// probePort returns true if something is listening on host:port
+func probePort(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// delugeConfigDirs returns the usual places for the deluge config dir: the user's,
+// the deluge user's on Debian/Ubuntu, linuxserver/deluge in Docker, and Windows
+func delugeConfigDirs() []string {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", "deluge"))
+	}
+	dirs = append(dirs, "/var/lib/deluge/.config/deluge", "/config")
+	if appData := os.Getenv("APPDATA"); appData != "" {
+		dirs = append(dirs, filepath.Join(appData, "deluge"))
+	}
+	return dirs
+}
+
+// qbitConfigFiles returns the usual places for qBittorrent.conf: the user's,
+// linuxserver/qbittorrent in Docker, and Windows
+func qbitConfigFiles() []string {
+	var files []string
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".config", "qBittorrent", "qBittorrent.conf"))
+	}
+	files = append(files, "/config/qBittorrent/qBittorrent.conf")
+	if appData := os.Getenv("APPDATA"); appData != "" {
+		files = append(files, filepath.Join(appData, "qBittorrent", "qBittorrent.ini"))
+	}
+	return files
+}
+
+// parseDelugeAuth returns a user from a deluge auth file of "username:password:level" lines,
+// preferring localclient since tt config init only looks at localhost
+func parseDelugeAuth(data string) (username string, password string, ok bool) {
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		if !ok || parts[0] == "localclient" {
+			username, password, ok = parts[0], parts[1], true
+		}
+	}
+	return username, password, ok
+}
+
+var delugeDaemonPortRegexp = regexp.MustCompile(`"daemon_port"\s*:\s*(\d+)`)
+
+// parseDelugeCoreConf returns the daemon_port from deluge's core.conf
+func parseDelugeCoreConf(data string) (int, bool) {
+	m := delugeDaemonPortRegexp.FindStringSubmatch(data)
+	if m == nil {
+		return 0, false
+	}
+	port, err := strconv.Atoi(m[1])
+	return port, err == nil
+}
+
+// parseQbitConf returns the WebUI port and username from qBittorrent.conf
+func parseQbitConf(data string) (port int, username string) {
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case `WebUI\Port`:
+			port, _ = strconv.Atoi(value)
+		case `WebUI\Username`:
+			username = value
+		}
+	}
+	return port, username
+}
+
+// redactSettings returns a copy of settings with the values of password keys replaced
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			redacted[key] = redactSettings(value)
+		default:
+			if key == "password" && value != "" {
+				value = "REDACTED"
+			}
+			redacted[key] = value
+		}
+	}
+	return redacted
 }
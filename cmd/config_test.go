@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDelugeAuth(t *testing.T) {
+	username, password, ok := parseDelugeAuth("admin:secret:10\nlocalclient:abc123:10\n")
+	assert.True(t, ok)
+	assert.Equal(t, "localclient", username)
+	assert.Equal(t, "abc123", password)
+
+	username, password, ok = parseDelugeAuth("# comment\nadmin:secret:10\n")
+	assert.True(t, ok)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "secret", password)
+
+	_, _, ok = parseDelugeAuth("")
+	assert.False(t, ok)
+}
+
+func TestParseLocalConfigs(t *testing.T) {
+	port, username := parseQbitConf("[Preferences]\nWebUI\\Port=8081\nWebUI\\Username=kenstir\nWebUI\\Password_PBKDF2=\"@ByteArray(xyz)\"\n")
+	assert.Equal(t, 8081, port)
+	assert.Equal(t, "kenstir", username)
+
+	port, ok := parseDelugeCoreConf("{\n    \"file\": 1,\n    \"format\": 1\n}{\n    \"daemon_port\": 58850,\n    \"dht\": true\n}")
+	assert.True(t, ok)
+	assert.Equal(t, 58850, port)
+}
+
+func TestRenderConfig(t *testing.T) {
+	p := sampleConfig
+	p.DelugeFound = false
+	content := renderConfig(p)
+	assert.Contains(t, content, "#[deluge]\n#server = \"192.168.1.222\"\n")
+	assert.Contains(t, content, "\n[qbit]\nserver = \"http://192.168.1.222:8080\"\n")
+	assert.False(t, strings.Contains(content, "##"))
+}
+
+func TestRedactSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"qbit": map[string]interface{}{
+			"password":      "secret",
+			"password_file": "/run/secrets/qbit",
+			"racing":        map[string]interface{}{"password": "secret2"},
+		},
+		"verbose": 1,
+	}
+	redacted := redactSettings(settings)
+	qbit := redacted["qbit"].(map[string]interface{})
+	assert.Equal(t, "REDACTED", qbit["password"])
+	assert.Equal(t, "/run/secrets/qbit", qbit["password_file"])
+	assert.Equal(t, "REDACTED", qbit["racing"].(map[string]interface{})["password"])
+	assert.Equal(t, "secret", settings["qbit"].(map[string]interface{})["password"])
+}
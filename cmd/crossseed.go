@@ -0,0 +1,258 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/moistari/rls"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type CrossseedOptions struct {
+	Filter   string
+	Inject   bool
+	NoHeader bool
+}
+
+// crossseedCandidate is a torrent that might be seeded from data another torrent already has
+type crossseedCandidate struct {
+	Hash string
+	Name string
+	data []byte // .torrent contents, loaded on demand
+}
+
+func init() {
+	rootCmd.AddCommand(crossseedCmd)
+	crossseedCmd.AddCommand(crossseedScanCmd)
+
+	crossseedScanCmd.Flags().String("from", "", "Find candidates among the torrents in this client, \"deluge\" or \"qbit\"")
+	crossseedScanCmd.Flags().StringP("dir", "d", "", "Find candidates among the .torrent files in DIR")
+	crossseedScanCmd.Flags().String("to", "qbit", "Match against the data of torrents in this client, \"qbit\" or \"deluge\"")
+	crossseedScanCmd.Flags().StringP("filter", "f", "", "Only consider candidates whose name matches")
+	crossseedScanCmd.Flags().Bool("inject", false, "Add matches to the --to client, paused with skip-check")
+	crossseedScanCmd.Flags().BoolP("noheader", "n", false, "Don't print the header line")
+	viper.BindPFlag("crossseed.scan.from", crossseedScanCmd.Flags().Lookup("from"))
+	viper.BindPFlag("crossseed.scan.dir", crossseedScanCmd.Flags().Lookup("dir"))
+	viper.BindPFlag("crossseed.scan.to", crossseedScanCmd.Flags().Lookup("to"))
+	viper.BindPFlag("crossseed.scan.filter", crossseedScanCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("crossseed.scan.inject", crossseedScanCmd.Flags().Lookup("inject"))
+	viper.BindPFlag("crossseed.scan.noheader", crossseedScanCmd.Flags().Lookup("noheader"))
+}
+
+var crossseedCmd = &cobra.Command{
+	Use:     "crossseed",
+	Aliases: []string{"xs"},
+	Short:   "Find cross-seed candidates",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var crossseedScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Find torrents whose data is already present in another torrent's save path",
+	Long: `Find candidate torrents, from one client (--from) or a directory of .torrent
+files (--dir), whose files are already present with the same sizes in the save
+path of a torrent in the --to client with the same release title.  Matches are
+printed as CSV, and with --inject added to the --to client in the paused state
+with skip-check, using the save path and category of the matching torrent.
+
+The save paths must be readable from this host, see [pathmap].`,
+	Args: cobra.NoArgs,
+	Run:  crossseedScanCmdRun,
+}
+
+func crossseedScanCmdRun(cmd *cobra.Command, args []string) {
+	from := viper.GetString("crossseed.scan.from")
+	dir := viper.GetString("crossseed.scan.dir")
+	to := viper.GetString("crossseed.scan.to")
+	opts := CrossseedOptions{
+		Filter:   viper.GetString("crossseed.scan.filter"),
+		Inject:   viper.GetBool("crossseed.scan.inject"),
+		NoHeader: viper.GetBool("crossseed.scan.noheader"),
+	}
+	if (from == "") == (dir == "") {
+		fatalError(fmt.Errorf("give exactly one of --from or --dir"))
+	}
+	if from == to {
+		fatalError(fmt.Errorf("--from and --to are both %s", from))
+	}
+
+	// connect
+	ctx := context.Background()
+	target, err := migrateConnect(ctx, to)
+	if err != nil {
+		fatalError(err)
+	}
+	defer target.close()
+
+	// collect candidates
+	var candidates []crossseedCandidate
+	var export func(ctx context.Context, hash string) ([]byte, error)
+	if dir != "" {
+		candidates, err = crossseedLoadDir(dir)
+	} else {
+		var source migrateClient
+		source, err = migrateConnect(ctx, from)
+		if err != nil {
+			fatalError(err)
+		}
+		defer source.close()
+		candidates, err = crossseedLoadClient(ctx, source)
+		export = source.export
+	}
+	if err != nil {
+		fatalError(err)
+	}
+
+	err = crossseedScan(ctx, candidates, export, target, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+// crossseedLoadDir returns the .torrent files in dir as candidates
+func crossseedLoadDir(dir string) ([]crossseedCandidate, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.torrent"))
+	if err != nil {
+		return nil, err
+	}
+	var candidates []crossseedCandidate
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		mi, err := parseMetainfo(data)
+		if err != nil {
+			logErrorf("%s: %v\n", path, err)
+			continue
+		}
+		// clients identify v2-only torrents by the truncated v2 info-hash
+		hash := mi.Hash
+		if hash == "" {
+			hash = mi.HashV2[:40]
+		}
+		candidates = append(candidates, crossseedCandidate{Hash: hash, Name: mi.Name, data: data})
+	}
+	return candidates, nil
+}
+
+// crossseedLoadClient returns the torrents in a client as candidates, without their .torrent files
+func crossseedLoadClient(ctx context.Context, source migrateClient) ([]crossseedCandidate, error) {
+	torrents, err := source.list(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []crossseedCandidate
+	for _, t := range torrents {
+		candidates = append(candidates, crossseedCandidate{Hash: t.Hash, Name: t.Name})
+	}
+	return candidates, nil
+}
+
+// crossseedKey is what two releases must have in common to be worth comparing files
+func crossseedKey(name string) string {
+	r := rls.ParseString(name)
+	return fmt.Sprintf("%s|%d|%d|%d", rls.MustNormalize(r.Title), r.Year, r.Series, r.Episode)
+}
+
+func crossseedScan(ctx context.Context, candidates []crossseedCandidate, export func(ctx context.Context, hash string) ([]byte, error), target migrateClient, opts CrossseedOptions) error {
+	torrents, err := target.list(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// index the target torrents by release
+	existing := make(map[string]bool)
+	byKey := make(map[string][]migrateTorrent)
+	for _, t := range torrents {
+		existing[t.Hash] = true
+		key := crossseedKey(t.Name)
+		byKey[key] = append(byKey[key], t)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+
+	if !opts.NoHeader {
+		fmt.Printf("hash,name,match_hash,save_path\n")
+	}
+	numFailed := 0
+	for _, c := range candidates {
+		if !matchesFilter(c.Name, opts.Filter) || existing[c.Hash] {
+			continue
+		}
+		others := byKey[crossseedKey(c.Name)]
+		if len(others) == 0 {
+			continue
+		}
+
+		// compare files only for candidates with a matching release
+		if c.data == nil {
+			c.data, err = export(ctx, c.Hash)
+			if err != nil {
+				logErrorf("%s: Error exporting: %v\n", c.Hash, err)
+				numFailed++
+				continue
+			}
+		}
+		mi, err := parseMetainfo(c.data)
+		if err != nil {
+			logErrorf("%s: %v\n", c.Hash, err)
+			numFailed++
+			continue
+		}
+		match, ok := crossseedFindMatch(mi.Files, others)
+		if !ok {
+			vLogf("%s: no data for \"%s\"\n", c.Hash, c.Name)
+			continue
+		}
+		fmt.Printf("%s,%s,%s,%s\n", c.Hash, c.Name, match.Hash, match.SavePath)
+
+		if opts.Inject {
+			t := migrateTorrent{Hash: c.Hash, Name: c.Name, SavePath: match.SavePath, Category: match.Category}
+			err = target.add(ctx, t, c.data, MigrateOptions{Paused: true, SkipCheck: true})
+			if err != nil {
+				logErrorf("%s: Error adding: %v\n", c.Hash, err)
+				numFailed++
+				continue
+			}
+			existing[c.Hash] = true
+		}
+	}
+
+	if numFailed > 0 {
+		return fmt.Errorf("%d torrents could not be checked or added", numFailed)
+	}
+	return nil
+}
+
+// crossseedFindMatch returns the first torrent whose save path has all the files, with the same sizes
+func crossseedFindMatch(files []torrentFile, others []migrateTorrent) (migrateTorrent, bool) {
+	for _, t := range others {
+		if crossseedHasFiles(t.SavePath, files) {
+			return t, true
+		}
+	}
+	return migrateTorrent{}, false
+}
+
+func crossseedHasFiles(savePath string, files []torrentFile) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		fi, err := os.Stat(filepath.Join(savePath, filepath.FromSlash(f.Path)))
+		if err != nil || !fi.Mode().IsRegular() || fi.Size() != f.Size {
+			vvLogf("\"%s\": no match for \"%s\"\n", savePath, f.Path)
+			return false
+		}
+	}
+	return true
+}
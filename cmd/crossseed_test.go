@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestCrossseedScan_Inject(t *testing.T) {
+	hash := "8694d6007ae15e276cbda435c410f6e2b6bd6f76"
+	emptyDir := t.TempDir()
+	wrongSizeDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(wrongSizeDir, "a.txt"), []byte("1234"), 0644))
+	dataDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dataDir, "a.txt"), []byte("12345"), 0644))
+
+	ctx := context.Background()
+	qbitClient := mocks.NewQbitMockClient()
+	qbitClient.On("GetTorrentsCtx", ctx, qbittorrent.TorrentFilterOptions{Sort: "name"}).Return([]qbittorrent.Torrent{
+		{Hash: "1111111111111111111111111111111111111111", Name: "a.txt", SavePath: emptyDir},
+		{Hash: "2222222222222222222222222222222222222222", Name: "a.txt", SavePath: wrongSizeDir},
+		{Hash: "3333333333333333333333333333333333333333", Name: "a.txt", SavePath: dataDir, Category: "misc"},
+		{Hash: "4444444444444444444444444444444444444444", Name: "b.txt", SavePath: dataDir},
+	}, nil)
+	qbitClient.On("GetCategoriesCtx", ctx).Return(map[string]qbittorrent.Category{"misc": {Name: "misc"}}, nil)
+	qbitClient.On("AddTorrentFromMemoryCtx", ctx, testTorrent, mock.MatchedBy(func(o map[string]string) bool {
+		return o["savepath"] == dataDir && o["category"] == "misc" && o["paused"] == "true" && o["skip_checking"] == "true"
+	})).Return(nil)
+
+	candidates := []crossseedCandidate{{Hash: hash, Name: "a.txt", data: testTorrent}}
+	target := &qbitMigrateClient{client: qbitClient}
+	err := crossseedScan(ctx, candidates, nil, target, CrossseedOptions{Inject: true})
+	assert.NoError(t, err)
+
+	qbitClient.AssertExpectations(t)
+}
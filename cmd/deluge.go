@@ -4,6 +4,9 @@ Copyright © 2025 Kenneth H. Cox
 package cmd
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/autobrr/go-deluge"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -16,29 +19,92 @@ func init() {
 	delugeCmd.PersistentFlags().IntP("port", "p", 9091, "server port")
 	delugeCmd.PersistentFlags().StringP("username", "U", "admin", "server username")
 	delugeCmd.PersistentFlags().StringP("password", "P", "password", "server password")
+	delugeCmd.PersistentFlags().String("instance", "", "server instance from a [deluge.NAME] section of the config")
 	viper.BindPFlag("deluge.server", delugeCmd.PersistentFlags().Lookup("server"))
 	viper.BindPFlag("deluge.port", delugeCmd.PersistentFlags().Lookup("port"))
 	viper.BindPFlag("deluge.username", delugeCmd.PersistentFlags().Lookup("username"))
 	viper.BindPFlag("deluge.password", delugeCmd.PersistentFlags().Lookup("password"))
+	viper.BindPFlag("deluge.instance", delugeCmd.PersistentFlags().Lookup("instance"))
 }
 
 var delugeCmd = &cobra.Command{
 	Use:     "deluge",
 	Aliases: []string{"d"},
 	Short:   "Manage a deluge server",
+	Long: `Manage a deluge server.
+
+Most qbit commands have a deluge equivalent.  "tt deluge recheck" fails, because
+the deluge client library has no call for it; use the Deluge UI or
+"deluge-console recheck" instead.  "tt deluge force-start" turns off auto_managed
+for the torrents for good, see its help.
+`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
+// delugeCreateV2Client creates a client for the instance selected by --instance
 func delugeCreateV2Client() *deluge.ClientV2 {
-	if viper.GetInt("verbose") > 0 {
-		stdoutLogger.Printf("Connecting to %s:%d as user %s\n", viper.GetString("deluge.server"), viper.GetInt("deluge.port"), viper.GetString("deluge.username"))
+	return delugeCreateInstanceClient(currentInstance("deluge"))
+}
+
+func delugeCreateInstanceClient(instance string) *deluge.ClientV2 {
+	client, err := delugeNewInstanceClient(instance)
+	if err != nil {
+		fatalError(err)
+	}
+	return client
+}
+
+// delugeNewInstanceClient is delugeCreateInstanceClient for callers that handle errors themselves
+func delugeNewInstanceClient(instance string) (*deluge.ClientV2, error) {
+	server := viper.GetString(delugeInstanceKey(instance, "server"))
+	port := viper.GetInt(delugeInstanceKey(instance, "port"))
+	username := viper.GetString(delugeInstanceKey(instance, "username"))
+	password, err := instancePassword(delugeCmd.PersistentFlags(), "deluge", instance)
+	if err != nil {
+		return nil, err
 	}
+	vLogf("Connecting to %s:%d as user %s\n", server, port, username)
 	return deluge.NewV2(deluge.Settings{
-		Hostname: viper.GetString("deluge.server"),
-		Port:     uint(viper.GetInt("deluge.port")),
-		Login:    viper.GetString("deluge.username"),
-		Password: viper.GetString("deluge.password"),
-	})
+		Hostname: server,
+		Port:     uint(port),
+		Login:    username,
+		Password: password,
+	}), nil
+}
+
+// delugeInstanceKey returns the viper key holding a connection setting for instance
+func delugeInstanceKey(instance string, key string) string {
+	return instanceKey(delugeCmd.PersistentFlags(), "deluge", instance, key)
+}
+
+// delugeLabelClient is implemented by deluge clients that can use the Label plugin
+type delugeLabelClient interface {
+	LabelPlugin(ctx context.Context) (*deluge.LabelPlugin, error)
+}
+
+// delugeLabelPlugin returns the Label plugin, or an error if it is not enabled
+func delugeLabelPlugin(ctx context.Context, client deluge.DelugeClient) (*deluge.LabelPlugin, error) {
+	lc, ok := client.(delugeLabelClient)
+	if !ok {
+		return nil, fmt.Errorf("deluge client does not support labels")
+	}
+	plugin, err := lc.LabelPlugin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil {
+		return nil, fmt.Errorf("deluge Label plugin is not enabled")
+	}
+	return plugin, nil
+}
+
+// delugeGetLabels returns the label of each torrent with the given hashes, or all torrents if none are given
+func delugeGetLabels(ctx context.Context, client deluge.DelugeClient, hashes []string) (map[string]string, error) {
+	plugin, err := delugeLabelPlugin(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.GetTorrentsLabels(deluge.StateUnspecified, hashes)
 }
@@ -0,0 +1,187 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	delugeCmd.AddCommand(delugeAddCmd)
+
+	delugeAddCmd.Flags().StringP("save-path", "s", "", "Download location (host path, see [pathmap])")
+	delugeAddCmd.Flags().StringP("label", "l", "", "Label (requires the Label plugin)")
+	delugeAddCmd.Flags().BoolP("paused", "p", false, "Add in the paused state")
+	delugeAddCmd.Flags().Bool("skip-check", false, "Skip the hash check (seed mode)")
+	delugeAddCmd.Flags().BoolP("reannounce", "r", false, "Reannounce each torrent until healthy, per the reannounce settings")
+	delugeAddCmd.Flags().StringP("watch", "w", "", "Watch DIR for .torrent and .magnet files")
+	delugeAddCmd.Flags().Int("interval", 10, "Seconds between scans of the watch dir")
+	delugeAddCmd.Flags().Bool("once", false, "Scan the watch dir once and exit")
+	viper.BindPFlag("deluge.add.save-path", delugeAddCmd.Flags().Lookup("save-path"))
+	viper.BindPFlag("deluge.add.label", delugeAddCmd.Flags().Lookup("label"))
+	viper.BindPFlag("deluge.add.paused", delugeAddCmd.Flags().Lookup("paused"))
+	viper.BindPFlag("deluge.add.skip-check", delugeAddCmd.Flags().Lookup("skip-check"))
+	viper.BindPFlag("deluge.add.reannounce", delugeAddCmd.Flags().Lookup("reannounce"))
+	viper.BindPFlag("deluge.add.watch", delugeAddCmd.Flags().Lookup("watch"))
+	viper.BindPFlag("deluge.add.interval", delugeAddCmd.Flags().Lookup("interval"))
+	viper.BindPFlag("deluge.add.once", delugeAddCmd.Flags().Lookup("once"))
+}
+
+var delugeAddCmd = &cobra.Command{
+	Use:   "add [FILE.torrent|FILE.magnet|MAGNET_URI]...",
+	Short: "Add torrents",
+	Long: `Add torrents from .torrent files, .magnet files or magnet URIs.
+
+With --watch DIR, add every .torrent and .magnet file that appears in DIR,
+renaming each to *.added or *.failed afterwards.  Files still being written are
+left alone until they stop changing between scans.`,
+	Run: delugeAddCmdRun,
+}
+
+func delugeAddCmdRun(cmd *cobra.Command, args []string) {
+	opts := AddOptions{
+		SavePath:   viper.GetString("deluge.add.save-path"),
+		Category:   viper.GetString("deluge.add.label"),
+		Paused:     viper.GetBool("deluge.add.paused"),
+		SkipCheck:  viper.GetBool("deluge.add.skip-check"),
+		Reannounce: viper.GetBool("deluge.add.reannounce"),
+		Watch:      viper.GetString("deluge.add.watch"),
+		Interval:   viper.GetInt("deluge.add.interval"),
+		Once:       viper.GetBool("deluge.add.once"),
+	}
+
+	// create a deluge client
+	client := delugeCreateV2Client()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := delugeAdd(ctx, client, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func delugeAdd(ctx context.Context, client deluge.DelugeClient, args []string, opts AddOptions) error {
+	if len(args) == 0 && opts.Watch == "" {
+		return fmt.Errorf("nothing to add; give a file, a magnet URI, or --watch DIR")
+	}
+
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// create the label if needed
+	var plugin *deluge.LabelPlugin
+	if opts.Category != "" {
+		plugin, err = delugeLabelPlugin(ctx, client)
+		if err != nil {
+			return err
+		}
+		existing, err := plugin.GetLabels(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(existing, opts.Category) {
+			logf("creating label \"%s\"\n", opts.Category)
+			err = plugin.AddLabel(ctx, opts.Category)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// reannounces run in the background, each with its own connection
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	add := func(src addSource) error {
+		hash, err := delugeAddSource(ctx, client, src, opts)
+		if err != nil {
+			return err
+		}
+		if plugin != nil {
+			err = plugin.SetTorrentLabel(ctx, hash, opts.Category)
+			if err != nil {
+				return fmt.Errorf("error setting label: %v", err)
+			}
+		}
+		if opts.Reannounce {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := delugeReannounce(ctx, delugeCreateV2Client(), hash, delugeReannounceOptions())
+				if err != nil {
+					logErrorf("%s: %v\n", hash, err)
+				}
+			}()
+		}
+		return nil
+	}
+
+	// add files and magnets
+	numFailed := 0
+	for _, arg := range args {
+		src, err := loadAddSource(arg)
+		if err == nil {
+			err = add(src)
+		}
+		if err != nil {
+			logErrorf("%s: %v\n", arg, err)
+			numFailed++
+		}
+	}
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d torrents could not be added", numFailed, len(args))
+	}
+
+	// watch
+	if opts.Watch != "" {
+		return watchDir(ctx, opts.Watch, time.Duration(opts.Interval)*time.Second, opts.Once, add)
+	}
+	return nil
+}
+
+func delugeAddSource(ctx context.Context, client deluge.DelugeClient, src addSource, opts AddOptions) (string, error) {
+	options := &deluge.Options{}
+	if opts.SavePath != "" {
+		savePath := clientPath(opts.SavePath)
+		options.DownloadLocation = &savePath
+	}
+	if opts.Paused {
+		options.AddPaused = &opts.Paused
+	}
+	if opts.SkipCheck {
+		options.V2.SeedMode = &opts.SkipCheck
+	}
+
+	var hash string
+	var err error
+	if src.Magnet != "" {
+		hash, err = client.AddTorrentMagnet(ctx, src.Magnet, options)
+	} else {
+		hash, err = client.AddTorrentFile(ctx, filepath.Base(src.Path), base64.StdEncoding.EncodeToString(src.Data), options)
+	}
+	if err != nil {
+		return "", err
+	}
+	if hash == "" {
+		return "", fmt.Errorf("%s: deluge did not return a hash, is it already added?", src.Hash)
+	}
+	logf("%s: added \"%s\"\n", hash, src.name())
+	return hash, nil
+}
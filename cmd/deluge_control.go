@@ -0,0 +1,153 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// delugeControlAction is a bulk command that applies one API call to the selected torrents.
+// A nil Apply means the deluge client library has no such call.
+type delugeControlAction struct {
+	Name    string
+	Aliases []string
+	Short   string
+	Note    string // appended to the Long help
+	Apply   func(ctx context.Context, client deluge.DelugeClient, hashes []string) error
+}
+
+var delugeControlActions = []delugeControlAction{
+	{
+		Name:    "pause",
+		Aliases: []string{"stop"},
+		Short:   "Pause torrents",
+		Apply: func(ctx context.Context, client deluge.DelugeClient, hashes []string) error {
+			return client.PauseTorrents(ctx, hashes...)
+		},
+	},
+	{
+		Name:    "resume",
+		Aliases: []string{"unpause"},
+		Short:   "Resume torrents",
+		Apply: func(ctx context.Context, client deluge.DelugeClient, hashes []string) error {
+			return client.ResumeTorrents(ctx, hashes...)
+		},
+	},
+	{
+		Name:    "recheck",
+		Aliases: []string{"check", "verify"},
+		Short:   "Recheck torrents (not supported)",
+		Note: `The deluge client library has no recheck call, so this always fails.  Use the
+Deluge UI or "deluge-console recheck" instead.`,
+	},
+	{
+		Name:    "force-start",
+		Aliases: []string{"force"},
+		Short:   "Force start torrents by turning off auto-managed, ignoring queue limits",
+		Note: `Deluge has no separate force-start, so this turns off auto_managed for the
+torrents for good; the queue will not pause or start them again until you turn
+auto_managed back on, e.g. in the torrent's Options tab.`,
+		Apply: func(ctx context.Context, client deluge.DelugeClient, hashes []string) error {
+			autoManaged := false
+			for _, hash := range hashes {
+				err := client.SetTorrentOptions(ctx, hash, &deluge.Options{AutoManaged: &autoManaged})
+				if err != nil {
+					return fmt.Errorf("%s: %v", hash, err)
+				}
+			}
+			return client.ResumeTorrents(ctx, hashes...)
+		},
+	},
+}
+
+func init() {
+	for _, action := range delugeControlActions {
+		cmd := &cobra.Command{
+			Use:     action.Name + " [hash]...",
+			Aliases: action.Aliases,
+			Short:   action.Short,
+			Long:    delugeControlLong(action),
+			Run: func(cmd *cobra.Command, args []string) {
+				delugeControlCmdRun(action, args)
+			},
+		}
+		delugeCmd.AddCommand(cmd)
+
+		cmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+		cmd.Flags().Bool("all", false, "Select all torrents")
+		viper.BindPFlag("deluge."+action.Name+".filter", cmd.Flags().Lookup("filter"))
+		viper.BindPFlag("deluge."+action.Name+".all", cmd.Flags().Lookup("all"))
+	}
+}
+
+// delugeControlLong returns the Long help for an action
+func delugeControlLong(action delugeControlAction) string {
+	long := action.Short + " selected by hash, by --filter, or --all."
+	if action.Note != "" {
+		long += "\n\n" + action.Note
+	}
+	return long
+}
+
+func delugeControlCmdRun(action delugeControlAction, args []string) {
+	opts := ControlOptions{
+		Filter: viper.GetString("deluge." + action.Name + ".filter"),
+		All:    viper.GetBool("deluge." + action.Name + ".all"),
+	}
+
+	// create a deluge client
+	client := delugeCreateV2Client()
+
+	err := delugeControl(context.Background(), client, action, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func delugeControl(ctx context.Context, client deluge.DelugeClient, action delugeControlAction, hashes []string, opts ControlOptions) error {
+	if action.Apply == nil {
+		return fmt.Errorf("%s is not supported by the deluge client library", action.Name)
+	}
+	if len(hashes) == 0 && opts.Filter == "" && !opts.All {
+		return fmt.Errorf("no torrents specified; give a hash, --filter, or --all")
+	}
+
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// find torrents
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	var selected []string
+	for _, hash := range delugeSortedKeys(torrentsStatus) {
+		ts := torrentsStatus[hash]
+		if !matchesFilter(ts.Name, opts.Filter) {
+			continue
+		}
+		selected = append(selected, hash)
+		vLogf("%s: %s \"%s\"\n", hash, action.Name, ts.Name)
+	}
+	if len(selected) == 0 {
+		vLogf("No torrents to %s\n", action.Name)
+		return nil
+	}
+
+	err = action.Apply(ctx, client, selected)
+	if err != nil {
+		return err
+	}
+	logf("%s: %d torrents\n", action.Name, len(selected))
+	return nil
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelugeRecheckCmd_NotSupported(t *testing.T) {
+	_, stdout, stderr := newTestLogSession(t)
+
+	code := runTT(t, "deluge", "check", "--all")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout.String()+stderr.String(), "recheck is not supported by the deluge client library")
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	delugeCmd.AddCommand(delugeExportCmd)
+
+	delugeExportCmd.Flags().StringP("dir", "d", "", "Directory to export to")
+	delugeExportCmd.Flags().String("state-dir", "", "Deluge state directory holding HASH.torrent files, e.g. ~/.config/deluge/state")
+	delugeExportCmd.Flags().Bool("by-name", false, "Name files \"NAME [HASH].torrent\" instead of \"HASH.torrent\"")
+	delugeExportCmd.Flags().Bool("by-label", false, "Export into a subdirectory per label (requires the Label plugin)")
+	delugeExportCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	delugeExportCmd.Flags().BoolP("dry-run", "n", false, "Print what would be exported")
+	viper.BindPFlag("deluge.export.dir", delugeExportCmd.Flags().Lookup("dir"))
+	viper.BindPFlag("deluge.state-dir", delugeExportCmd.Flags().Lookup("state-dir"))
+	viper.BindPFlag("deluge.export.by-name", delugeExportCmd.Flags().Lookup("by-name"))
+	viper.BindPFlag("deluge.export.by-label", delugeExportCmd.Flags().Lookup("by-label"))
+	viper.BindPFlag("deluge.export.filter", delugeExportCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("deluge.export.dry-run", delugeExportCmd.Flags().Lookup("dry-run"))
+}
+
+var delugeExportCmd = &cobra.Command{
+	Use:   "export [hash]...",
+	Short: "Export .torrent files for backup",
+	Long: `Export the .torrent file of each torrent to --dir, skipping torrents
+already exported there.  Safe to run from cron.
+
+The deluge RPC API cannot return .torrent files, so they are copied from the
+daemon's state directory, which must be readable from this host.`,
+	Run: delugeExportCmdRun,
+}
+
+func delugeExportCmdRun(cmd *cobra.Command, args []string) {
+	opts := ExportOptions{
+		Dir:        viper.GetString("deluge.export.dir"),
+		ByName:     viper.GetBool("deluge.export.by-name"),
+		ByCategory: viper.GetBool("deluge.export.by-label"),
+		Filter:     viper.GetString("deluge.export.filter"),
+		DryRun:     viper.GetBool("deluge.export.dry-run"),
+	}
+	stateDir := viper.GetString("deluge.state-dir")
+
+	// create a deluge client
+	client := delugeCreateV2Client()
+
+	err := delugeExport(context.Background(), client, args, stateDir, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func delugeExport(ctx context.Context, client deluge.DelugeClient, hashes []string, stateDir string, opts ExportOptions) error {
+	if opts.Dir == "" {
+		return fmt.Errorf("no export directory; give --dir")
+	}
+	if stateDir == "" {
+		return fmt.Errorf("no deluge state directory; give --state-dir or set deluge.state-dir")
+	}
+
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// find torrents and their labels
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	var labels map[string]string
+	if opts.ByCategory {
+		labels, err = delugeGetLabels(ctx, client, hashes)
+		if err != nil {
+			return err
+		}
+	}
+	var items []exportItem
+	for _, hash := range delugeSortedKeys(torrentsStatus) {
+		items = append(items, exportItem{Hash: hash, Name: torrentsStatus[hash].Name, Category: labels[hash]})
+	}
+
+	return exportTorrents(items, opts, func(hash string) ([]byte, error) {
+		return delugeReadStateTorrent(stateDir, hash)
+	})
+}
+
+// delugeReadStateTorrent reads HASH.torrent from the deluge state directory and
+// checks that it really is that torrent
+func delugeReadStateTorrent(stateDir string, hash string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(stateDir, hash+".torrent"))
+	if err != nil {
+		return nil, err
+	}
+	infoHash, err := torrentInfoHash(data)
+	if err != nil {
+		return nil, err
+	}
+	if infoHash != hash {
+		return nil, fmt.Errorf("state file has info-hash %s", infoHash)
+	}
+	return data, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	delugeCmd.AddCommand(delugeLabelCmd)
+	delugeLabelCmd.AddCommand(delugeLabelListCmd)
+	delugeLabelCmd.AddCommand(delugeLabelSetCmd)
+
+	delugeLabelSetCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	delugeLabelSetCmd.Flags().BoolP("dry-run", "n", false, "Print the changes without making them")
+	viper.BindPFlag("deluge.label.filter", delugeLabelSetCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("deluge.label.dry-run", delugeLabelSetCmd.Flags().Lookup("dry-run"))
+}
+
+var delugeLabelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage labels (requires the Label plugin)",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var delugeLabelListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List labels",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := delugeLabelList(context.Background(), delugeCreateV2Client())
+		if err != nil {
+			fatalError(err)
+		}
+	},
+}
+
+var delugeLabelSetCmd = &cobra.Command{
+	Use:   "set LABEL [hash]...",
+	Short: "Set the label of torrents selected by hash or --filter",
+	Long: `Set the label of torrents selected by hash or --filter, creating the label if needed.
+Use "" as the LABEL to remove the label.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := TagOptions{
+			Filter: viper.GetString("deluge.label.filter"),
+			DryRun: viper.GetBool("deluge.label.dry-run"),
+		}
+		err := delugeLabelSet(context.Background(), delugeCreateV2Client(), args[0], args[1:], opts)
+		if err != nil {
+			fatalError(err)
+		}
+	},
+}
+
+func delugeLabelList(ctx context.Context, client deluge.DelugeClient) error {
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plugin, err := delugeLabelPlugin(ctx, client)
+	if err != nil {
+		return err
+	}
+	labels, err := plugin.GetLabels(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(labels)
+
+	fmt.Printf("label\n")
+	for _, label := range labels {
+		fmt.Printf("%s\n", label)
+	}
+	return nil
+}
+
+func delugeLabelSet(ctx context.Context, client deluge.DelugeClient, label string, hashes []string, opts TagOptions) error {
+	if len(hashes) == 0 && opts.Filter == "" {
+		return fmt.Errorf("no torrents specified; give a hash or --filter")
+	}
+
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// find torrents and their labels
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	plugin, err := delugeLabelPlugin(ctx, client)
+	if err != nil {
+		return err
+	}
+	labels, err := plugin.GetTorrentsLabels(deluge.StateUnspecified, hashes)
+	if err != nil {
+		return err
+	}
+
+	// create the label if needed
+	if label != "" && !opts.DryRun {
+		existing, err := plugin.GetLabels(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(existing, label) {
+			logf("creating label \"%s\"\n", label)
+			err = plugin.AddLabel(ctx, label)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// set labels
+	var lastError error
+	for _, hash := range delugeSortedKeys(torrentsStatus) {
+		ts := torrentsStatus[hash]
+		if !matchesFilter(ts.Name, opts.Filter) || labels[hash] == label {
+			continue
+		}
+		logf("%s: label \"%s\" -> \"%s\" \"%s\"\n", hash, labels[hash], label, ts.Name)
+		if opts.DryRun {
+			continue
+		}
+		err = plugin.SetTorrentLabel(ctx, hash, label)
+		if err != nil {
+			logErrorf("%s: Error setting label: %v\n", hash, err)
+			lastError = err
+		}
+	}
+
+	return lastError
+}
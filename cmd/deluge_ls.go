@@ -23,6 +23,7 @@ type ListOptions struct {
 	Tag      string // qbit only
 	NoHeader bool
 	Humanize bool
+	Instance string // for the instance column
 }
 
 func init() {
@@ -47,12 +48,15 @@ var delugeValidColumns = []string{
 	"downloaded",
 	"group",
 	"hash",
+	"instance",
+	"label",
 	"name",
 	"next_announce",
 	"ratio",
 	"reannounce",
 	"save_path",
 	"seed_time",
+	"size",
 	"state",
 	"status",
 	"uploaded",
@@ -71,14 +75,14 @@ func delugeListCmdRun(cmd *cobra.Command, args []string) {
 
 	// check flags
 	columns := viper.GetStringSlice("deluge.columns")
-	for _, column := range columns {
-		if !slices.Contains(delugeValidColumns, column) {
-			fatalError(fmt.Errorf("unknown column: %s (expected one of {%s})", column, strings.Join(delugeValidColumns, ", ")))
-		}
+	if err := checkColumns(columns, delugeValidColumns); err != nil {
+		fatalError(err)
 	}
 
-	// create a deluge client
-	client := delugeCreateV2Client()
+	instances, err := selectInstances("deluge", viper.GetString("deluge.instance"))
+	if err != nil {
+		fatalError(err)
+	}
 
 	// collect options and go
 	opts := ListOptions{
@@ -87,7 +91,12 @@ func delugeListCmdRun(cmd *cobra.Command, args []string) {
 		NoHeader: viper.GetBool("deluge.noheader"),
 		Humanize: viper.GetBool("deluge.humanize"),
 	}
-	err := delugeList(context.Background(), client, hashes, opts)
+	err = forEachInstance(instances, func(i int, instance string) error {
+		instanceOpts := opts
+		instanceOpts.Instance = instance
+		instanceOpts.NoHeader = opts.NoHeader || i > 0
+		return delugeList(context.Background(), delugeCreateInstanceClient(instance), hashes, instanceOpts)
+	})
 	if err != nil {
 		fatalError(err)
 	}
@@ -102,29 +111,53 @@ func delugeList(ctx context.Context, client deluge.DelugeClient, hashes []string
 	defer client.Close()
 	vLogf("Connected to deluge\n")
 
+	// get torrents
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	vLogf("Found %d torrents\n", len(torrentsStatus))
+
+	// labels come from a plugin, so only get them if needed
+	var labels map[string]string
+	if slices.Contains(opts.Columns, "label") {
+		labels, err = delugeGetLabels(ctx, client, hashes)
+		if err != nil {
+			return err
+		}
+	}
+
+	delugePrintTorrents(torrentsStatus, labels, opts)
+	return nil
+}
+
+// delugeGetTorrentsStatus returns the status of the torrents with the given hashes, or all torrents if none are given
+func delugeGetTorrentsStatus(ctx context.Context, client deluge.DelugeClient, hashes []string) (map[string]*deluge.TorrentStatus, error) {
 	// the `ids` argument to TorrentsStatus has to be nil to list all torrents
 	ids := hashes
 	if len(ids) == 0 {
 		ids = nil
 	}
 
-	// get torrents
 	torrentsStatus, err := client.TorrentsStatus(ctx, deluge.StateUnspecified, ids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// check that all specified torrents were found
 	if len(hashes) > 0 && len(hashes) != len(torrentsStatus) {
 		for _, hash := range hashes {
 			if _, ok := torrentsStatus[hash]; !ok {
-				return fmt.Errorf("%s: torrent not found", hash)
+				return nil, fmt.Errorf("%s: torrent not found", hash)
 			}
 		}
 	}
-	vLogf("Found %d torrents\n", len(torrentsStatus))
 
-	// sort torrentsStatus by name
+	return torrentsStatus, nil
+}
+
+// delugeSortedKeys returns the keys of torrentsStatus sorted by torrent name
+func delugeSortedKeys(torrentsStatus map[string]*deluge.TorrentStatus) []string {
 	keys := make([]string, 0, len(torrentsStatus))
 	for key := range torrentsStatus {
 		keys = append(keys, key)
@@ -132,16 +165,20 @@ func delugeList(ctx context.Context, client deluge.DelugeClient, hashes []string
 	sort.Slice(keys, func(i, j int) bool {
 		return torrentsStatus[keys[i]].Name < torrentsStatus[keys[j]].Name
 	})
+	return keys
+}
 
-	// print as CSV
+// delugePrintTorrents prints the torrents that match opts.Filter as CSV, sorted by name.
+// labels may be nil if the label column is not used.
+func delugePrintTorrents(torrentsStatus map[string]*deluge.TorrentStatus, labels map[string]string, opts ListOptions) {
 	if !opts.NoHeader {
 		fmt.Printf("%s\n", strings.Join(opts.Columns, ","))
 	}
-	for _, key := range keys {
+	for _, key := range delugeSortedKeys(torrentsStatus) {
 		ts := torrentsStatus[key]
 
 		// skip if the name doesn't match the filter
-		if opts.Filter != "" && !strings.Contains(strings.ToLower(ts.Name), strings.ToLower(opts.Filter)) {
+		if !matchesFilter(ts.Name, opts.Filter) {
 			continue
 		}
 
@@ -149,16 +186,18 @@ func delugeList(ctx context.Context, client deluge.DelugeClient, hashes []string
 		var line []string
 		r := rls.ParseString(ts.Name)
 		for _, column := range opts.Columns {
-			line = append(line, delugeFormatColumn(column, ts, r, opts.Humanize))
+			if column == "instance" {
+				line = append(line, opts.Instance)
+				continue
+			}
+			line = append(line, delugeFormatColumn(column, ts, labels[key], r, opts.Humanize))
 		}
 		fmt.Printf("%s\n", strings.Join(line, ","))
 	}
-
-	return nil
 }
 
 // format the given column
-func delugeFormatColumn(column string, ts *deluge.TorrentStatus, r rls.Release, humanize bool) string {
+func delugeFormatColumn(column string, ts *deluge.TorrentStatus, label string, r rls.Release, humanize bool) string {
 	switch column {
 	case "added":
 		return formatTimestamp(int64(ts.TimeAdded))
@@ -169,7 +208,7 @@ func delugeFormatColumn(column string, ts *deluge.TorrentStatus, r rls.Release,
 	case "completed":
 		return formatTimestamp(ts.CompletedTime)
 	case "download_location":
-		return ts.DownloadLocation
+		return hostPath(ts.DownloadLocation)
 	case "downloaded":
 		// waiting on https://github.com/autobrr/go-deluge/pull/8
 		// if humanize {
@@ -181,6 +220,8 @@ func delugeFormatColumn(column string, ts *deluge.TorrentStatus, r rls.Release,
 		return r.Group
 	case "hash":
 		return ts.Hash
+	case "label":
+		return label
 	case "name":
 		return ts.Name
 	case "next_announce", "reannounce":
@@ -188,9 +229,14 @@ func delugeFormatColumn(column string, ts *deluge.TorrentStatus, r rls.Release,
 	case "ratio":
 		return fmt.Sprintf("%.1f", ts.Ratio)
 	case "save_path":
-		return ts.SavePath // same as ts.DownloadLocation but easier to type
+		return hostPath(ts.SavePath) // same as ts.DownloadLocation but easier to type
 	case "seed_time":
 		return (time.Duration(ts.SeedingTime) * time.Second).String()
+	case "size":
+		if humanize {
+			return humanizeBytes(ts.TotalSize)
+		}
+		return fmt.Sprintf("%d", ts.TotalSize)
 	case "state":
 		return ts.State
 	case "status":
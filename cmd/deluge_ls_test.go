@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelugeList(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	server.AddTorrent(deluge.TorrentStatus{Hash: "a", Name: "Some.Show.S01E01", State: "Seeding", Ratio: 1.5})
+	server.AddTorrent(deluge.TorrentStatus{Hash: "b", Name: "Other.Movie", State: "Downloading"})
+
+	var err error
+	out := captureStdout(t, func() {
+		err = delugeList(context.Background(), client, nil, ListOptions{Columns: []string{"hash", "state", "name"}, Filter: "show"})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hash,state,name\na,Seeding,Some.Show.S01E01\n", out)
+}
+
+func TestDelugeList_NotFound(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	server.AddTorrent(deluge.TorrentStatus{Hash: "a", Name: "Movie"})
+
+	err := delugeList(context.Background(), client, []string{"a", "missing"}, ListOptions{Columns: []string{"hash"}})
+	assert.EqualError(t, err, "missing: torrent not found")
+}
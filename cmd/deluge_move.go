@@ -6,7 +6,8 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
+	"time"
 
 	"github.com/autobrr/go-deluge"
 	"github.com/spf13/cobra"
@@ -17,7 +18,11 @@ func init() {
 	delugeCmd.AddCommand(delugeMoveCmd)
 
 	delugeMoveCmd.Flags().BoolP("force", "f", false, "Force move")
+	delugeMoveCmd.Flags().BoolP("wait", "w", false, "Wait for the move to finish and verify the files")
+	delugeMoveCmd.Flags().IntP("timeout", "t", 60*60, "Maximum time to wait in seconds")
 	viper.BindPFlag("deluge.move.force", delugeMoveCmd.Flags().Lookup("force"))
+	viper.BindPFlag("deluge.move.wait", delugeMoveCmd.Flags().Lookup("wait"))
+	viper.BindPFlag("deluge.move.timeout", delugeMoveCmd.Flags().Lookup("timeout"))
 }
 
 var delugeMoveCmd = &cobra.Command{
@@ -34,29 +39,27 @@ func delugeMoveCmdRun(cmd *cobra.Command, args []string) {
 
 	// get the flags
 	force := viper.GetBool("deluge.move.force")
+	opts := MoveOptions{
+		Wait:    viper.GetBool("deluge.move.wait"),
+		Timeout: viper.GetInt("deluge.move.timeout"),
+	}
 
-	// OMG, msys does path conversion, turning "/a" into "c:/Program Files/Git/a".
-	// Do not allow this.
-	if os.Getenv("MSYSTEM") != "" {
-		if os.Getenv("MSYS_NO_PATHCONV") != "1" {
-			stdoutLogger.Printf("Warning: MSYSTEM=%s, msys path conversion is in effect\n", os.Getenv("MSYSTEM"))
-			if !force {
-				fatalError(fmt.Errorf("msys path conversion in effect, rerun with MSYS_NO_PATHCONV=1 or --force"))
-			}
-		}
+	if err := checkMsysPathConversion(force); err != nil {
+		fatalError(err)
 	}
 
 	// create a deluge client
 	client := delugeCreateV2Client()
 
 	// move
-	err := delugeMove(context.Background(), client, hash, path)
+	err := delugeMove(context.Background(), client, hash, path, opts)
 	if err != nil {
 		fatalError(err)
 	}
 }
 
-func delugeMove(ctx context.Context, client deluge.DelugeClient, hash string, path string) error {
+// delugeMove moves a torrent to path, given as a path on this host
+func delugeMove(ctx context.Context, client deluge.DelugeClient, hash string, path string, opts MoveOptions) error {
 	// connect
 	err := client.Connect(ctx)
 	if err != nil {
@@ -65,11 +68,44 @@ func delugeMove(ctx context.Context, client deluge.DelugeClient, hash string, pa
 	defer client.Close()
 
 	// move
-	stdoutLogger.Printf("%s: requesting move to \"%s\"\n", hash, path)
-	err = client.MoveStorage(ctx, []string{hash}, path)
+	dest := clientPath(path)
+	if dest != path {
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, ""), "%s: pathmap: \"%s\" -> \"%s\"\n", hash, path, dest)
+	}
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, ""), "%s: requesting move to \"%s\"\n", hash, dest)
+	err = client.MoveStorage(ctx, []string{hash}, dest)
 	if err != nil {
 		return err
 	}
 
+	// wait for it
+	if opts.Wait {
+		return waitForMove(hash, path, time.Duration(opts.Timeout)*time.Second, func() (moveStatus, error) {
+			return delugeGetMoveStatus(ctx, client, hash)
+		})
+	}
+
 	return nil
 }
+
+func delugeGetMoveStatus(ctx context.Context, client deluge.DelugeClient, hash string) (moveStatus, error) {
+	ts, err := delugeGetTorrentStatus(ctx, client, hash)
+	if err != nil {
+		return moveStatus{}, err
+	}
+	status := moveStatus{
+		Moving:   ts.State == string(deluge.StateMoving),
+		SavePath: hostPath(ts.SavePath),
+	}
+	if ts.State == string(deluge.StateError) {
+		// the tracker status may be unrelated, e.g. "Announce OK", but it is all deluge tells us
+		status.Error = "torrent in error state"
+		if ts.TrackerStatus != "" {
+			status.Error = fmt.Sprintf("torrent in error state: %s", ts.TrackerStatus)
+		}
+	}
+	for _, f := range ts.Files {
+		status.Files = append(status.Files, f.Path)
+	}
+	return status, nil
+}
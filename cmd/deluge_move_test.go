@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelugeMove_Wait(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	dest := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dest, "movie.mkv"), []byte("x"), 0644))
+	server.AddTorrent(deluge.TorrentStatus{
+		Hash:     "a",
+		Name:     "Movie",
+		SavePath: "/scratch",
+		Files:    []deluge.File{{Path: "movie.mkv", Size: 1}},
+	})
+
+	err := delugeMove(context.Background(), client, "a", dest, MoveOptions{Wait: true, Timeout: 10})
+	assert.NoError(t, err)
+
+	ts, _ := server.Torrent("a")
+	assert.Equal(t, dest, ts.SavePath)
+	assert.Contains(t, server.Calls(), "core.move_storage")
+}
+
+func TestDelugeMove_WaitErrorState(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	server.AddTorrent(deluge.TorrentStatus{Hash: "a", Name: "Movie", SavePath: "/scratch", State: "Error"})
+
+	err := delugeMove(context.Background(), client, "a", t.TempDir(), MoveOptions{Wait: true, Timeout: 10})
+	assert.EqualError(t, err, "a: move failed: torrent in error state")
+}
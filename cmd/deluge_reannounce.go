@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -40,24 +41,11 @@ var delugeReannounceCmd = &cobra.Command{
 func delugeReannounceCmdRun(cmd *cobra.Command, args []string) {
 	hash := args[0]
 
-	// get the flags
-	attempts := viper.GetInt("deluge.reannounce.attempts")
-	interval := viper.GetInt("deluge.reannounce.interval")
-	extraAttempts := viper.GetInt("deluge.reannounce.extra_attempts")
-	extraInterval := viper.GetInt("deluge.reannounce.extra_interval")
-	maxAge := viper.GetInt("deluge.reannounce.max_age")
-
 	// create a deluge client
 	client := delugeCreateV2Client()
 
 	// reannounce
-	options := ReannounceOptions{
-		Attempts:      attempts,
-		Interval:      interval,
-		ExtraAttempts: extraAttempts,
-		ExtraInterval: extraInterval,
-		MaxAge:        maxAge,
-	}
+	options := delugeReannounceOptions()
 	err := delugeReannounce(context.Background(), client, hash, options)
 	if err != nil {
 		logErrorf("%v\n", err)
@@ -65,6 +53,17 @@ func delugeReannounceCmdRun(cmd *cobra.Command, args []string) {
 	}
 }
 
+// delugeReannounceOptions returns the reannounce options from flags or config
+func delugeReannounceOptions() ReannounceOptions {
+	return ReannounceOptions{
+		Attempts:      viper.GetInt("deluge.reannounce.attempts"),
+		Interval:      viper.GetInt("deluge.reannounce.interval"),
+		ExtraAttempts: viper.GetInt("deluge.reannounce.extra_attempts"),
+		ExtraInterval: viper.GetInt("deluge.reannounce.extra_interval"),
+		MaxAge:        viper.GetInt("deluge.reannounce.max_age"),
+	}
+}
+
 func delugeReannounce(ctx context.Context, client deluge.DelugeClient, hash string, opts ReannounceOptions) error {
 
 	// connect
@@ -76,7 +75,7 @@ func delugeReannounce(ctx context.Context, client deluge.DelugeClient, hash stri
 	vLogf("Connected to deluge\n")
 
 	// log something at startup
-	stdoutLogger.Printf("%s: Started\n", hash)
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, ""), "%s: Started\n", hash)
 
 	// reannounce loops
 	err = delugeReannounceUntilOK(ctx, client, hash, opts)
@@ -103,10 +102,8 @@ func delugeReannounceUntilOK(ctx context.Context, client deluge.DelugeClient, ha
 		prefix := fmt.Sprintf("try %d", i)
 
 		// delay before every attempt
-		if verbosity > 0 {
-			stdoutLogger.Printf("%s: %s: sleep %d\n", hash, prefix, options.Interval)
-		}
-		time.Sleep(time.Duration(options.Interval) * time.Second)
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, prefix), "%s: %s: sleep %d\n", hash, prefix, options.Interval)
+		time.Sleep(time.Duration(options.Interval) * reannounceIntervalUnit)
 
 		// get torrent status
 		ts, err := delugeGetTorrentStatus(ctx, client, hash)
@@ -118,16 +115,16 @@ func delugeReannounceUntilOK(ctx context.Context, client deluge.DelugeClient, ha
 		// maybe skip or reannounce based on status
 		skipReannounce, ok := delugeCheckStatus(ts)
 		if skipReannounce {
-			stdoutLogger.Printf("%s: %s: skipping reannounce\n", hash, prefix)
+			logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: skipping reannounce\n", hash, prefix)
 		} else if ok {
-			stdoutLogger.Printf("%s: %s: torrent is OK with seeds=%d total_seeds=%d\n", hash, prefix, ts.NumSeeds, ts.TotalSeeds)
+			logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: torrent is OK with seeds=%d total_seeds=%d\n", hash, prefix, ts.NumSeeds, ts.TotalSeeds)
 			return nil
 		} else {
 			delugeForceReannounce(ctx, client, hash, prefix)
 		}
 	}
 
-	stdoutLogger.Printf("%s: Reannounce attempts exhausted", hash)
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, ""), "%s: Reannounce attempts exhausted", hash)
 	return nil
 }
 
@@ -136,10 +133,8 @@ func delugeReannounceForGoodMeasure(ctx context.Context, client deluge.DelugeCli
 		prefix := fmt.Sprintf("extra %d", i)
 
 		// delay before every attempt
-		if verbosity > 0 {
-			stdoutLogger.Printf("%s: %s: sleep %d\n", hash, prefix, options.ExtraInterval)
-		}
-		time.Sleep(time.Duration(options.ExtraInterval) * time.Second)
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, prefix), "%s: %s: sleep %d\n", hash, prefix, options.ExtraInterval)
+		time.Sleep(time.Duration(options.ExtraInterval) * reannounceIntervalUnit)
 
 		// log torrent status
 		ts, err := delugeGetTorrentStatus(ctx, client, hash)
@@ -192,14 +187,14 @@ func delugeCheckStatus(ts *deluge.TorrentStatus) (bool, bool) {
 
 func delugeForceReannounce(ctx context.Context, client deluge.DelugeClient, hash string, prefix string) {
 	if err := client.ForceReannounce(ctx, []string{hash}); err != nil {
-		logErrorf("%s: Error reannouncing: %s\n", hash, err)
+		logAttrsf(slog.LevelError, torrentAttrs(hash, prefix), "%s: Error reannouncing: %s\n", hash, err)
 	} else {
-		logf("%s: %s: reannounce requested\n", hash, prefix)
+		logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: reannounce requested\n", hash, prefix)
 	}
 }
 
 func delugeLogTorrentStatus(ctx context.Context, ts *deluge.TorrentStatus, prefix string) {
 	duration := time.Duration(ts.NextAnnounce) * time.Second
 	progress := int(ts.Progress + 0.5)
-	logf("%s: %s: torrent: status=\"%s\" seeds=%d total_seeds=%d peer=%d progress=%d%% reannounce=%d(%s)\n", ts.Hash, prefix, ts.TrackerStatus, ts.NumSeeds, ts.TotalSeeds, ts.TotalPeers, progress, ts.NextAnnounce, duration.String())
+	logAttrsf(slog.LevelInfo, append(torrentAttrs(ts.Hash, prefix), slog.String("tracker", ts.TrackerHost)), "%s: %s: torrent: status=\"%s\" seeds=%d total_seeds=%d peer=%d progress=%d%% reannounce=%d(%s)\n", ts.Hash, prefix, ts.TrackerStatus, ts.NumSeeds, ts.TotalSeeds, ts.TotalPeers, progress, ts.NextAnnounce, duration.String())
 }
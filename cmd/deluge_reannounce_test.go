@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kenstir/tortle/internal/fakedeluge"
+)
+
+// newFakeDeluge starts a fake Deluge daemon with reannounce intervals in microseconds
+func newFakeDeluge(t *testing.T) (*fakedeluge.Server, deluge.DelugeClient) {
+	server, err := fakedeluge.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	saved := reannounceIntervalUnit
+	reannounceIntervalUnit = time.Microsecond
+	t.Cleanup(func() { reannounceIntervalUnit = saved })
+	return server, deluge.NewV2(server.Settings())
+}
+
+func TestDelugeReannounce(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	ctx := context.Background()
+	hash := "testhash"
+	server.AddTorrent(deluge.TorrentStatus{Hash: hash, Name: "Movie", TrackerHost: "example.org", TrackerStatus: "Error: unregistered torrent"})
+	server.OnReannounce = func(ts *deluge.TorrentStatus, n int) {
+		if n == 2 {
+			ts.TrackerStatus = "Announce OK"
+			ts.NumSeeds = 3
+		}
+	}
+
+	opts := ReannounceOptions{Attempts: 5, Interval: 7, ExtraAttempts: 1, ExtraInterval: 30}
+	err := delugeReannounce(ctx, client, hash, opts)
+	assert.NoError(t, err)
+
+	// two until OK, then one for good measure
+	assert.Equal(t, 3, server.Reannounces(hash))
+}
+
+func TestDelugeReannounce_BadLogin(t *testing.T) {
+	server, _ := newFakeDeluge(t)
+	settings := server.Settings()
+	settings.Password = "wrong"
+
+	err := delugeReannounce(context.Background(), deluge.NewV2(settings), "testhash", ReannounceOptions{Attempts: 1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "BadLoginError")
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	delugeCmd.AddCommand(delugeRmCmd)
+
+	delugeRmCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	delugeRmCmd.Flags().BoolP("dry-run", "n", false, "List the torrents that would be removed")
+	delugeRmCmd.Flags().BoolP("keep-files", "k", false, "Remove the torrents but keep their files")
+	delugeRmCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+	delugeRmCmd.Flags().Bool("purge-links", false, "Also purge hard-linked copies of the files in purge.scan-path")
+	viper.BindPFlag("deluge.rm.filter", delugeRmCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("deluge.rm.dry-run", delugeRmCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("deluge.rm.keep-files", delugeRmCmd.Flags().Lookup("keep-files"))
+	viper.BindPFlag("deluge.rm.yes", delugeRmCmd.Flags().Lookup("yes"))
+	viper.BindPFlag("deluge.rm.purge-links", delugeRmCmd.Flags().Lookup("purge-links"))
+}
+
+var delugeRmCmd = &cobra.Command{
+	Use:     "rm [hash]...",
+	Aliases: []string{"remove", "del", "delete"},
+	Short:   "Remove torrents",
+	Long: `Remove torrents from Deluge by their hash or by name.
+
+You will be asked to confirm before anything is removed; use --yes when running
+from a script.  A torrent that cannot be removed is reported and the rest are
+still removed.`,
+	Run: delugeRmCmdRun,
+}
+
+func delugeRmCmdRun(cmd *cobra.Command, args []string) {
+	// check flags
+	columns := viper.GetStringSlice("deluge.columns")
+	if err := checkColumns(columns, delugeValidColumns); err != nil {
+		fatalError(err)
+	}
+
+	// create a deluge client
+	client := delugeCreateV2Client()
+
+	// collect options and go
+	opts := RmOptions{
+		Filter:     viper.GetString("deluge.rm.filter"),
+		DryRun:     viper.GetBool("deluge.rm.dry-run"),
+		KeepFiles:  viper.GetBool("deluge.rm.keep-files"),
+		Yes:        viper.GetBool("deluge.rm.yes"),
+		PurgeLinks: viper.GetBool("deluge.rm.purge-links"),
+		ScanPaths:  viper.GetStringSlice("purge.scan-path"),
+		List: ListOptions{
+			Columns:  columns,
+			Humanize: viper.GetBool("deluge.humanize"),
+			Instance: currentInstance("deluge"),
+		},
+	}
+	err := delugeRm(context.Background(), client, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func delugeRm(ctx context.Context, client deluge.DelugeClient, hashes []string, opts RmOptions) error {
+	// refuse to remove everything
+	if len(hashes) == 0 && opts.Filter == "" {
+		return fmt.Errorf("no torrents specified; give a hash or --filter")
+	}
+	if opts.PurgeLinks && len(opts.ScanPaths) == 0 {
+		return fmt.Errorf("--purge-links requires purge.scan-path")
+	}
+
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	vLogf("Connected to deluge\n")
+
+	// find torrents
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	for hash, ts := range torrentsStatus {
+		if !matchesFilter(ts.Name, opts.Filter) {
+			delete(torrentsStatus, hash)
+		}
+	}
+	if len(torrentsStatus) == 0 {
+		vLogf("No torrents to remove\n")
+		return nil
+	}
+
+	// in dry-run mode, just list them
+	if opts.DryRun {
+		delugePrintTorrents(torrentsStatus, nil, opts.List)
+		return nil
+	}
+
+	// ask first
+	if !opts.Yes {
+		delugePrintTorrents(torrentsStatus, nil, ListOptions{Columns: []string{"name", "size", "ratio"}, Humanize: true})
+		if !confirm(rmConfirmPrompt(len(torrentsStatus), opts)) {
+			return fmt.Errorf("not confirmed, nothing removed")
+		}
+	}
+
+	// find hard-linked files now, since removing the torrents may delete them
+	var lastError error
+	selected := delugeSortedKeys(torrentsStatus)
+	targets := map[string]*purgeTargets{}
+	if opts.PurgeLinks {
+		for _, hash := range selected {
+			ts := torrentsStatus[hash]
+			pt, err := findPurgeTargets(osPurgeFS, filepath.Join(hostPath(ts.SavePath), ts.Name))
+			if err != nil {
+				logErrorf("%s: error purging links: %v\n", hash, err)
+				lastError = err
+				continue
+			}
+			targets[hash] = pt
+		}
+	}
+
+	// remove torrents, reporting failures individually
+	torrentErrors, err := client.RemoveTorrents(ctx, selected, !opts.KeepFiles)
+	if err != nil {
+		return err
+	}
+	failed := map[string]bool{}
+	for _, te := range torrentErrors {
+		logErrorf("%s: error removing: %s\n", te.ID, te.Message)
+		failed[te.ID] = true
+	}
+	for _, hash := range selected {
+		if !failed[hash] {
+			logf("%s: removed \"%s\"\n", hash, torrentsStatus[hash].Name)
+		}
+	}
+
+	// purge hard-linked copies of the removed torrents
+	for _, hash := range selected {
+		pt := targets[hash]
+		if pt == nil || failed[hash] {
+			continue
+		}
+		_, err = pt.purge(osPurgeFS, opts.ScanPaths, false)
+		if err != nil {
+			logErrorf("%s: error purging links: %v\n", hash, err)
+			lastError = err
+		}
+	}
+	if len(torrentErrors) > 0 {
+		return fmt.Errorf("%d of %d torrents could not be removed", len(torrentErrors), len(selected))
+	}
+
+	return lastError
+}
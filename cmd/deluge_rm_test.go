@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestDelugeRm_ReportsFailures(t *testing.T) {
+	mockClient := mocks.NewDelugeMockClient()
+	ctx := context.Background()
+	hashes := []string{"a", "b"}
+	torrentsStatus := map[string]*deluge.TorrentStatus{
+		"a": {Hash: "a", Name: "A"},
+		"b": {Hash: "b", Name: "B"},
+	}
+
+	mockClient.On("Connect", ctx).Return(nil)
+	mockClient.On("Close").Return(nil)
+	mockClient.On("TorrentsStatus", ctx, deluge.StateUnspecified, hashes).Return(torrentsStatus, nil)
+	mockClient.On("RemoveTorrents", ctx, []string{"a", "b"}, true).Return([]deluge.TorrentError{{ID: "b", Message: "boom"}}, nil)
+
+	err := delugeRm(ctx, mockClient, hashes, RmOptions{Yes: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2")
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestDelugeRm_FilterKeepFiles(t *testing.T) {
+	mockClient := mocks.NewDelugeMockClient()
+	ctx := context.Background()
+	var all []string
+	torrentsStatus := map[string]*deluge.TorrentStatus{
+		"a": {Hash: "a", Name: "Some.Show.S01E01"},
+		"b": {Hash: "b", Name: "Other.Movie"},
+	}
+
+	mockClient.On("Connect", ctx).Return(nil)
+	mockClient.On("Close").Return(nil)
+	mockClient.On("TorrentsStatus", ctx, deluge.StateUnspecified, all).Return(torrentsStatus, nil)
+	mockClient.On("RemoveTorrents", ctx, []string{"a"}, false).Return([]deluge.TorrentError{}, nil)
+
+	err := delugeRm(ctx, mockClient, nil, RmOptions{Filter: "show", KeepFiles: true, Yes: true})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestDelugeRm_DryRun(t *testing.T) {
+	mockClient := mocks.NewDelugeMockClient()
+	ctx := context.Background()
+	hashes := []string{"a"}
+	torrentsStatus := map[string]*deluge.TorrentStatus{
+		"a": {Hash: "a", Name: "A"},
+	}
+
+	mockClient.On("Connect", ctx).Return(nil)
+	mockClient.On("Close").Return(nil)
+	mockClient.On("TorrentsStatus", ctx, deluge.StateUnspecified, hashes).Return(torrentsStatus, nil)
+
+	err := delugeRm(ctx, mockClient, hashes, RmOptions{DryRun: true, List: ListOptions{Columns: []string{"hash"}}})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/autobrr/go-deluge"
 	"github.com/spf13/cobra"
@@ -14,6 +15,9 @@ import (
 
 func init() {
 	delugeCmd.AddCommand(delugeStatsCmd)
+
+	delugeStatsCmd.Flags().String("by", "", "Also print stats for each label (\"label\")")
+	viper.BindPFlag("deluge.stats.by", delugeStatsCmd.Flags().Lookup("by"))
 }
 
 var delugeStatsCmd = &cobra.Command{
@@ -23,17 +27,33 @@ var delugeStatsCmd = &cobra.Command{
 }
 
 func delugeStatsCmdRun(cmd *cobra.Command, args []string) {
-	// create a deluge client
-	client := delugeCreateV2Client()
+	// check flags
+	by := viper.GetString("deluge.stats.by")
+	if by != "" && by != "label" {
+		fatalError(fmt.Errorf("unknown breakdown: %s (expected label)", by))
+	}
+
+	// tag lines with the instance only if one was asked for
+	instanceFlag := viper.GetString("deluge.instance")
+	instances, err := selectInstances("deluge", instanceFlag)
+	if err != nil {
+		fatalError(err)
+	}
 
 	// get and print stats
-	err := delugeStats(context.Background(), client)
+	err = forEachInstance(instances, func(i int, instance string) error {
+		if instanceFlag == "" {
+			instance = ""
+		}
+		return delugeStats(context.Background(), delugeCreateInstanceClient(instance), instance, by)
+	})
 	if err != nil {
 		fatalError(err)
 	}
 }
 
-func delugeStats(ctx context.Context, client deluge.DelugeClient) error {
+// delugeStats prints stats for one instance, adding an instance tag unless instance is ""
+func delugeStats(ctx context.Context, client deluge.DelugeClient, instance string, by string) error {
 	// connect
 	err := client.Connect(ctx)
 	if err != nil {
@@ -51,8 +71,11 @@ func delugeStats(ctx context.Context, client deluge.DelugeClient) error {
 	// See also https://docs.influxdata.com/influxdb/v1/write_protocols/line_protocol_tutorial/
 	tags := []string{
 		"client_type=deluge",
-		fmt.Sprintf("client_host=%s", viper.GetString("deluge.server")),
-		fmt.Sprintf("client_port=%d", viper.GetInt("deluge.port")),
+		fmt.Sprintf("client_host=%s", viper.GetString(delugeInstanceKey(instance, "server"))),
+		fmt.Sprintf("client_port=%d", viper.GetInt(delugeInstanceKey(instance, "port"))),
+	}
+	if instance != "" {
+		tags = append(tags, fmt.Sprintf("instance=%s", escapeTagValue(instance)))
 	}
 	fields := []string{
 		// Seems nobody wants to see DownloadRate and UploadRate;
@@ -73,6 +96,32 @@ func delugeStats(ctx context.Context, client deluge.DelugeClient) error {
 	fields = append(fields, delugeStatsCalculatedFields(torrentsStatus)...)
 
 	printMeasurement("tt_stats", tags, fields)
+
+	// add a line per label
+	if by == "label" {
+		labels, err := delugeGetLabels(ctx, client, nil)
+		if err != nil {
+			return err
+		}
+		groups := map[string]map[string]*deluge.TorrentStatus{}
+		for hash, ts := range torrentsStatus {
+			label := labels[hash]
+			if groups[label] == nil {
+				groups[label] = map[string]*deluge.TorrentStatus{}
+			}
+			groups[label][hash] = ts
+		}
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			groupTags := append(tags[:len(tags):len(tags)], fmt.Sprintf("label=%s", escapeTagValue(name)))
+			printMeasurement("tt_stats", groupTags, delugeStatsCalculatedFields(groups[name]))
+		}
+	}
+
 	return nil
 }
 
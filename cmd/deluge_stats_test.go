@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelugeStats(t *testing.T) {
+	server, client := newFakeDeluge(t)
+	server.SessionStatus = deluge.SessionStatus{PayloadDownloadRate: 1024, PayloadUploadRate: 512, TotalDownload: 100, TotalUpload: 200}
+	server.AddTorrent(deluge.TorrentStatus{Hash: "a", Name: "A", State: "Seeding", UploadPayloadRate: 512})
+	server.AddTorrent(deluge.TorrentStatus{Hash: "b", Name: "B", State: "Downloading", DownloadPayloadRate: 1024})
+
+	var err error
+	out := captureStdout(t, func() {
+		err = delugeStats(context.Background(), client, "", "")
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "tt_stats,client_type=deluge,")
+	assert.Contains(t, out, " download_rate=1024.0,upload_rate=512.0,total_download=100u,total_upload=200u,")
+	assert.Contains(t, out, "seeding=1")
+	assert.Contains(t, out, "downloading=1")
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	delugeCmd.AddCommand(delugeTagCmd)
+
+	delugeTagCmd.Flags().Bool("auto", false, "Apply labels by tracker health and hard-link status")
+	delugeTagCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	delugeTagCmd.Flags().BoolP("dry-run", "n", false, "Print the changes without making them")
+	viper.BindPFlag("deluge.tag.auto", delugeTagCmd.Flags().Lookup("auto"))
+	viper.BindPFlag("deluge.tag.filter", delugeTagCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("deluge.tag.dry-run", delugeTagCmd.Flags().Lookup("dry-run"))
+}
+
+var delugeTagCmd = &cobra.Command{
+	Use:   "tag --auto [hash]...",
+	Short: "Label torrents by health",
+	Long: `Label every torrent (or the given ones) by tracker health using the Label plugin.
+
+Deluge allows only one label per torrent, so the first that applies is used:
+
+  unregistered   the tracker says the torrent is not registered
+  tracker-down   the tracker is not responding
+  not-working    the tracker reports an error
+  nohl           a completed torrent has no hard-linked files
+
+Only torrents with no label or one of these labels are changed, and stale labels
+are cleared, so it is safe to run from cron.  If a torrent's files cannot be read,
+its nohl label is left as it is.`,
+	Run: delugeTagCmdRun,
+}
+
+// deluge labels must be lowercase
+var delugeAutoLabels = []string{tagUnregistered, tagTrackerDown, tagNotWorking, strings.ToLower(tagNoHL)}
+
+func delugeTagCmdRun(cmd *cobra.Command, args []string) {
+	opts := TagOptions{
+		Auto:   viper.GetBool("deluge.tag.auto"),
+		Filter: viper.GetString("deluge.tag.filter"),
+		DryRun: viper.GetBool("deluge.tag.dry-run"),
+	}
+	if !opts.Auto {
+		fatalError(fmt.Errorf("only --auto is supported; use `tt deluge label set` to set labels"))
+	}
+
+	// create a deluge client
+	client := delugeCreateV2Client()
+
+	err := delugeAutoTag(context.Background(), client, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func delugeAutoTag(ctx context.Context, client deluge.DelugeClient, hashes []string, opts TagOptions) error {
+	// connect
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	vLogf("Connected to deluge\n")
+
+	// find torrents and their labels
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, client, hashes)
+	if err != nil {
+		return err
+	}
+	plugin, err := delugeLabelPlugin(ctx, client)
+	if err != nil {
+		return err
+	}
+	labels, err := plugin.GetTorrentsLabels(deluge.StateUnspecified, hashes)
+	if err != nil {
+		return err
+	}
+
+	// make sure the labels exist
+	if !opts.DryRun {
+		existing, err := plugin.GetLabels(ctx)
+		if err != nil {
+			return err
+		}
+		for _, label := range delugeAutoLabels {
+			if !slices.Contains(existing, label) {
+				err = plugin.AddLabel(ctx, label)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// relabel
+	for _, hash := range delugeSortedKeys(torrentsStatus) {
+		ts := torrentsStatus[hash]
+		if !matchesFilter(ts.Name, opts.Filter) {
+			continue
+		}
+		current := labels[hash]
+		if current != "" && !slices.Contains(delugeAutoLabels, current) {
+			vvLogf("%s: keeping label \"%s\"\n", hash, current)
+			continue
+		}
+		desired := delugeAutoLabel(ts, current)
+		if desired == current {
+			continue
+		}
+		logf("%s: label \"%s\" -> \"%s\" \"%s\"\n", hash, current, desired, ts.Name)
+		if opts.DryRun {
+			continue
+		}
+		err = plugin.SetTorrentLabel(ctx, hash, desired)
+		if err != nil {
+			logErrorf("%s: Error setting label: %v\n", hash, err)
+		}
+	}
+
+	return nil
+}
+
+// delugeAutoLabel returns the label the torrent should have, or "" for none;
+// current is the torrent's auto label now
+func delugeAutoLabel(ts *deluge.TorrentStatus, current string) string {
+	status := strings.ToLower(ts.TrackerStatus)
+	if c := classifyTrackerMessage(status); c != "" && !strings.HasPrefix(status, "announce ok") {
+		return c
+	}
+	if strings.HasPrefix(status, "error") {
+		return tagNotWorking
+	}
+	if ts.IsFinished {
+		linked, err := hasHardLinks(filepath.Join(hostPath(ts.SavePath), ts.Name))
+		if err != nil {
+			// keep the current label rather than flap while the path is unreadable
+			vLogf("%s: %v\n", ts.Hash, err)
+			if current == strings.ToLower(tagNoHL) {
+				return current
+			}
+		} else if !linked {
+			return strings.ToLower(tagNoHL)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// diskFreeSpace returns the bytes available to an unprivileged user on the file system containing path
+func diskFreeSpace(path string) (int64, error) {
+	var stat unix.Statfs_t
+	err := unix.Statfs(path, &stat)
+	if err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package cmd
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// diskFreeSpace returns the bytes available to the caller on the volume containing path
+func diskFreeSpace(path string) (int64, error) {
+	pathp, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytesAvailable uint64
+	err = windows.GetDiskFreeSpaceEx(pathp, &freeBytesAvailable, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return int64(freeBytesAvailable), nil
+}
@@ -0,0 +1,303 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+const (
+	doctorPass = "pass"
+	doctorFail = "FAIL"
+	doctorSkip = "skip"
+)
+
+// doctorCheck is one line of the tt doctor table
+type doctorCheck struct {
+	Name   string
+	Result string
+	Detail string
+}
+
+// configOnlyKeys are config keys that are read directly rather than through a flag
+var configOnlyKeys = []string{
+	"deluge.password_file",
+	"mover.rules",
+	"pathmap.prefixes",
+	"qbit.password_file",
+	"qbit.rules",
+}
+
+// instanceConfigKeys are the keys allowed in a [qbit.NAME] or [deluge.NAME] section
+var instanceConfigKeys = []string{"password", "password_file", "port", "server", "username"}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config and the connection to each client",
+	Long: `Check the config and the connection to each client, printing a table of
+results.  Checks that tt.toml has no unknown keys or invalid columns and rules,
+that every configured client accepts the login, that each purge.scan-path
+exists on the same filesystem as the download paths, and, when the log is
+rotated, that its directory is writable.  Exits 1 if any check fails.`,
+	Args: cobra.NoArgs,
+	Run:  doctorCmdRun,
+}
+
+func doctorCmdRun(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	var checks []doctorCheck
+
+	// config
+	checks = append(checks, doctorCheckConfigKeys()...)
+	checks = append(checks, doctorCheckColumns("qbit.columns", qbitValidColumns))
+	checks = append(checks, doctorCheckColumns("deluge.columns", delugeValidColumns))
+	checks = append(checks, doctorCheckRules()...)
+
+	// clients
+	var downloadPaths []string
+	for _, client := range []string{"qbit", "deluge"} {
+		if !doctorClientConfigured(client) {
+			checks = append(checks, doctorCheck{client, doctorSkip, "not configured"})
+			continue
+		}
+		for _, instance := range instanceNames(client) {
+			name := client
+			if instance != defaultInstance {
+				name += "." + instance
+			}
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			var detail string
+			var paths []string
+			var err error
+			if client == "qbit" {
+				var qc *internal.QbitClient
+				if qc, err = qbitNewInstanceClient(instance); err == nil {
+					detail, paths, err = doctorCheckQbit(ctx, qc)
+				}
+			} else {
+				var dc *deluge.ClientV2
+				if dc, err = delugeNewInstanceClient(instance); err == nil {
+					detail, paths, err = doctorCheckDeluge(ctx, dc)
+				}
+			}
+			cancel()
+			checks = append(checks, newDoctorCheck(name, detail, err))
+			downloadPaths = append(downloadPaths, paths...)
+		}
+	}
+
+	// files
+	checks = append(checks, doctorCheckScanPaths(viper.GetStringSlice("purge.scan-path"), downloadPaths, osPurgeFS)...)
+	checks = append(checks, doctorCheckLogDir(viper.GetString("log-file"), logRotateOptions()))
+
+	if !doctorPrint(checks) {
+		exitWithFinalizers(1)
+	}
+}
+
+func newDoctorCheck(name string, detail string, err error) doctorCheck {
+	if err != nil {
+		return doctorCheck{name, doctorFail, err.Error()}
+	}
+	return doctorCheck{name, doctorPass, detail}
+}
+
+// doctorPrint prints the checks as a table and returns true if none failed
+func doctorPrint(checks []doctorCheck) bool {
+	ok := true
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "CHECK\tRESULT\tDETAIL\n")
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, c.Result, c.Detail)
+		if c.Result == doctorFail {
+			ok = false
+		}
+	}
+	w.Flush()
+	return ok
+}
+
+// doctorCheckConfigKeys reads the config file again on its own, to find the keys it sets
+func doctorCheckConfigKeys() []doctorCheck {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return []doctorCheck{{"config", doctorSkip, "no config file found"}}
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return []doctorCheck{{"config", doctorFail, err.Error()}}
+	}
+	checks := []doctorCheck{{"config", doctorPass, file}}
+	for _, key := range doctorUnknownKeys(v.AllKeys(), flagKeys) {
+		checks = append(checks, doctorCheck{"config key", doctorFail, fmt.Sprintf("unknown key \"%s\"", key)})
+	}
+	return checks
+}
+
+// doctorUnknownKeys returns the keys in the config file that no flag, rule or instance uses
+func doctorUnknownKeys(fileKeys []string, flagKeys []string) []string {
+	var unknown []string
+	for _, key := range fileKeys {
+		if slices.Contains(flagKeys, key) || slices.Contains(configOnlyKeys, key) {
+			continue
+		}
+		// [qbit.NAME] and [deluge.NAME] sections
+		parts := strings.Split(key, ".")
+		if len(parts) == 3 && (parts[0] == "qbit" || parts[0] == "deluge") && slices.Contains(instanceConfigKeys, parts[2]) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func doctorCheckColumns(key string, validColumns []string) doctorCheck {
+	columns := viper.GetStringSlice(key)
+	if err := checkColumns(columns, validColumns); err != nil {
+		return doctorCheck{key, doctorFail, err.Error()}
+	}
+	return doctorCheck{key, doctorPass, strings.Join(columns, ",")}
+}
+
+func doctorCheckRules() []doctorCheck {
+	var checks []doctorCheck
+	if viper.IsSet("qbit.rules") {
+		rules, err := qbitLoadRemoveRules([]string{"all"})
+		checks = append(checks, newDoctorCheck("qbit.rules", fmt.Sprintf("%d rules", len(rules)), err))
+	}
+	if viper.IsSet("mover.rules") {
+		rules, err := moverLoadRules([]string{"all"})
+		checks = append(checks, newDoctorCheck("mover.rules", fmt.Sprintf("%d rules", len(rules)), err))
+	}
+	for _, entry := range viper.GetStringSlice("pathmap.prefixes") {
+		m, err := parsePathMapping(entry)
+		checks = append(checks, newDoctorCheck("pathmap", fmt.Sprintf("%s -> %s", m.Client, m.Host), err))
+	}
+	return checks
+}
+
+// doctorClientConfigured returns true if the config has a server for client
+func doctorClientConfigured(client string) bool {
+	return viper.IsSet(client+".server") || slices.ContainsFunc(instanceNames(client), func(name string) bool { return name != defaultInstance })
+}
+
+// doctorCheckQbit logs in and returns the versions and the host paths torrents are saved in
+func doctorCheckQbit(ctx context.Context, client internal.QbitClientInterface) (string, []string, error) {
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	appVersion, err := client.GetAppVersionCtx(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	apiVersion, err := client.GetWebAPIVersionCtx(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+	var paths []string
+	for _, t := range torrents {
+		paths = append(paths, hostPath(t.SavePath))
+	}
+	detail := fmt.Sprintf("qBittorrent %s, Web API %s, %d torrents", appVersion, apiVersion, len(torrents))
+	return detail, paths, nil
+}
+
+// doctorCheckDeluge connects and returns the version and the host paths torrents are saved in
+func doctorCheckDeluge(ctx context.Context, client deluge.DelugeClient) (string, []string, error) {
+	err := client.Connect(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer client.Close()
+	version, err := client.DaemonVersion(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	torrentsStatus, err := client.TorrentsStatus(ctx, deluge.StateUnspecified, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	var paths []string
+	for _, ts := range torrentsStatus {
+		paths = append(paths, hostPath(ts.SavePath))
+	}
+	detail := fmt.Sprintf("deluge %s, %d torrents", version, len(torrentsStatus))
+	return detail, paths, nil
+}
+
+// doctorCheckScanPaths checks that each scan path exists, and that each download path is on the
+// same filesystem as a scan path, since hard links cannot cross filesystems
+func doctorCheckScanPaths(scanPaths []string, downloadPaths []string, pfs purgeFS) []doctorCheck {
+	if len(scanPaths) == 0 {
+		return []doctorCheck{{"purge.scan-path", doctorSkip, "not configured"}}
+	}
+	var checks []doctorCheck
+	devices := map[uint64]bool{}
+	for _, path := range scanPaths {
+		stat, err := pfs.Lstat(path)
+		if err == nil && !stat.IsDir {
+			err = fmt.Errorf("%s: not a directory", path)
+		}
+		if err == nil {
+			devices[stat.Dev] = true
+		}
+		checks = append(checks, newDoctorCheck("purge.scan-path", path, err))
+	}
+
+	slices.Sort(downloadPaths)
+	for _, path := range slices.Compact(downloadPaths) {
+		stat, err := pfs.Lstat(path)
+		if err != nil {
+			checks = append(checks, doctorCheck{"download path", doctorFail, err.Error()})
+		} else if !devices[stat.Dev] {
+			checks = append(checks, doctorCheck{"download path", doctorFail, fmt.Sprintf("%s: not on the same filesystem as any purge.scan-path", path)})
+		}
+	}
+	return checks
+}
+
+// doctorCheckLogDir checks that files can be created next to the log file, which rotation
+// needs.  The log file itself was opened before doctor ran, so there is no point checking it.
+func doctorCheckLogDir(path string, rotate LogRotateOptions) doctorCheck {
+	if path == "" {
+		return doctorCheck{"log-file", doctorSkip, "logging to stdout"}
+	}
+	if !rotate.enabled() {
+		return doctorCheck{"log-file", doctorSkip, "not rotated"}
+	}
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, ".tt-doctor-*")
+	if err != nil {
+		return doctorCheck{"log-file", doctorFail, fmt.Sprintf("cannot rotate: %v", err)}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorCheck{"log-file", doctorPass, dir}
+}
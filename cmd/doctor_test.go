@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestDoctorUnknownKeys(t *testing.T) {
+	fileKeys := []string{"qbit.server", "qbit.seedbox.server", "qbit.seedbox.password_file", "qbit.rules", "qbit.sever", "purge.scanpath"}
+	flagKeys := []string{"qbit.server", "purge.scan-path"}
+
+	assert.Equal(t, []string{"purge.scanpath", "qbit.sever"}, doctorUnknownKeys(fileKeys, flagKeys))
+}
+
+func TestDoctorCheckQbit(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", SavePath: "/downloads"},
+	}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetAppVersionCtx", ctx).Return("v5.0.4", nil)
+	mockClient.On("GetWebAPIVersionCtx", ctx).Return("2.11.2", nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+
+	detail, paths, err := doctorCheckQbit(ctx, mockClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "qBittorrent v5.0.4, Web API 2.11.2, 1 torrents", detail)
+	assert.Equal(t, []string{"/downloads"}, paths)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestDoctorCheckScanPaths(t *testing.T) {
+	root := t.TempDir()
+	pfs := newFakePurgeFS()
+	pfs.stats[filepath.Join(root, "other")] = purgeStat{Dev: 2, IsDir: true}
+
+	checks := doctorCheckScanPaths([]string{root}, []string{root, filepath.Join(root, "other")}, pfs)
+	assert.Len(t, checks, 2)
+	assert.Equal(t, doctorPass, checks[0].Result)
+	assert.Equal(t, doctorFail, checks[1].Result)
+	assert.Contains(t, checks[1].Detail, "not on the same filesystem")
+}
+
+func TestDoctorCmd_BadPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "tt.toml")
+	config := "[qbit]\nserver = \"http://127.0.0.1:1\"\npassword_file = \"" + filepath.ToSlash(filepath.Join(dir, "missing")) + "\"\n"
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+	newTestLogSession(t)
+
+	// the bad instance is a row in the table rather than a fatal error
+	var code int
+	out := captureStdout(t, func() {
+		code = runTT(t, "--config", configPath, "doctor")
+	})
+	assert.Equal(t, 1, code)
+	assert.Regexp(t, `(?m)^qbit +FAIL +open .*missing`, out)
+	assert.Contains(t, out, "log-file")
+}
+
+func TestDoctorCheckLogDir(t *testing.T) {
+	dir := t.TempDir()
+	rotate := LogRotateOptions{MaxSize: 1 << 20}
+
+	assert.Equal(t, doctorSkip, doctorCheckLogDir("", rotate).Result)
+	assert.Equal(t, doctorSkip, doctorCheckLogDir(filepath.Join(dir, "tt.log"), LogRotateOptions{}).Result)
+	assert.Equal(t, doctorPass, doctorCheckLogDir(filepath.Join(dir, "tt.log"), rotate).Result)
+	assert.Equal(t, doctorFail, doctorCheckLogDir(filepath.Join(dir, "missing", "tt.log"), rotate).Result)
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
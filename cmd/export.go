@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ExportOptions struct {
+	Dir        string
+	ByName     bool // name files "NAME [HASH].torrent" instead of "HASH.torrent"
+	ByCategory bool // put files in a subdirectory per category or label
+	Filter     string
+	DryRun     bool
+}
+
+// exportItem is a torrent to be exported
+type exportItem struct {
+	Hash     string
+	Name     string
+	Category string
+}
+
+var exportHashRegexp = regexp.MustCompile(`[0-9a-f]{40}`)
+
+// exportIndex returns the hashes already exported anywhere under dir, found by
+// looking for a hash in each .torrent file name
+func exportIndex(dir string) (map[string]string, error) {
+	index := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() && strings.HasSuffix(d.Name(), ".torrent") {
+			if hash := exportHashRegexp.FindString(d.Name()); hash != "" {
+				index[hash] = path
+			}
+		}
+		return nil
+	})
+	return index, err
+}
+
+// exportPath returns where to write the .torrent file for item
+func exportPath(item exportItem, opts ExportOptions) string {
+	dir := opts.Dir
+	if opts.ByCategory && item.Category != "" {
+		for _, part := range strings.Split(item.Category, "/") {
+			dir = filepath.Join(dir, sanitizeFileName(part))
+		}
+	}
+	name := item.Hash + ".torrent"
+	if opts.ByName && item.Name != "" {
+		name = fmt.Sprintf("%s [%s].torrent", sanitizeFileName(item.Name), item.Hash)
+	}
+	return filepath.Join(dir, name)
+}
+
+// sanitizeFileName replaces characters that are not allowed in file names on some OS
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// exportTorrents writes a .torrent file for each item not already exported, using
+// fetch to get its contents
+func exportTorrents(items []exportItem, opts ExportOptions, fetch func(hash string) ([]byte, error)) error {
+	index, err := exportIndex(opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	numExported, numSkipped, numFailed := 0, 0, 0
+	for _, item := range items {
+		if !matchesFilter(item.Name, opts.Filter) {
+			continue
+		}
+		if path, ok := index[item.Hash]; ok {
+			vvLogf("%s: already exported to \"%s\"\n", item.Hash, path)
+			numSkipped++
+			continue
+		}
+		path := exportPath(item, opts)
+		logf("%s: export \"%s\"\n", item.Hash, path)
+		if opts.DryRun {
+			continue
+		}
+		err := exportTorrent(item.Hash, path, fetch)
+		if err != nil {
+			logErrorf("%s: Error exporting: %v\n", item.Hash, err)
+			numFailed++
+			continue
+		}
+		numExported++
+	}
+
+	vLogf("exported %d, skipped %d already exported\n", numExported, numSkipped)
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d torrents could not be exported", numFailed, numExported+numFailed)
+	}
+	return nil
+}
+
+// exportTorrent writes the .torrent file via a temp file, so an interrupted export
+// does not leave a truncated file that would be skipped next time
+func exportTorrent(hash string, path string, fetch func(hash string) ([]byte, error)) error {
+	data, err := fetch(hash)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("empty .torrent file")
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// defaultInstance names the server in the flat [qbit] or [deluge] section of tt.toml.
+// Other instances are configured in sections like [qbit.seedbox1] and selected with --instance.
+const defaultInstance = "default"
+
+// instanceKey returns the viper key holding a connection setting for an instance of client ("qbit" or "deluge").
+// A flag given on the command line wins, then the [client.NAME] section, then the flat [client] section.
+func instanceKey(flags *pflag.FlagSet, client string, instance string, key string) string {
+	if instance == "" || instance == defaultInstance || flags.Changed(key) {
+		return client + "." + key
+	}
+	k := client + "." + instance + "." + key
+	if viper.IsSet(k) {
+		return k
+	}
+	return client + "." + key
+}
+
+// instancePassword returns the password for an instance of client.  A password_file, e.g. a Docker
+// secret, takes precedence over a password in the same section, and the instance section over the flat one.
+func instancePassword(flags *pflag.FlagSet, client string, instance string) (string, error) {
+	if flags.Changed("password") {
+		return viper.GetString(client + ".password"), nil
+	}
+	sections := []string{client}
+	if instance != "" && instance != defaultInstance {
+		sections = []string{client + "." + instance, client}
+	}
+	for _, section := range sections {
+		if file := viper.GetString(section + ".password_file"); file != "" {
+			return readPasswordFile(file)
+		}
+		if viper.IsSet(section + ".password") {
+			return viper.GetString(section + ".password"), nil
+		}
+	}
+	return viper.GetString(client + ".password"), nil
+}
+
+// readPasswordFile returns the contents of file without the trailing newline
+func readPasswordFile(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// instanceNames returns the configured instances of client: the default instance if the flat section
+// has a server (or there are no others), then each [client.NAME] section with a server, sorted
+func instanceNames(client string) []string {
+	var names []string
+	for name, v := range viper.GetStringMap(client) {
+		if section, ok := v.(map[string]interface{}); ok {
+			if _, ok := section["server"]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	if viper.IsSet(client+".server") || len(names) == 0 {
+		names = append([]string{defaultInstance}, names...)
+	}
+	return names
+}
+
+// selectInstances resolves an --instance flag to the instances to run against; "all" means every one
+func selectInstances(client string, instance string) ([]string, error) {
+	names := instanceNames(client)
+	switch {
+	case instance == "all":
+		return names, nil
+	case instance == "" || instance == defaultInstance:
+		return []string{defaultInstance}, nil
+	case slices.Contains(names, instance):
+		return []string{instance}, nil
+	default:
+		return nil, fmt.Errorf("unknown %s instance \"%s\"; expected one of %v or \"all\"", client, instance, names)
+	}
+}
+
+// currentInstance returns the single instance selected by --instance, for commands that have no fan-out
+func currentInstance(client string) string {
+	instance := viper.GetString(client + ".instance")
+	if instance == "all" {
+		fatalError(fmt.Errorf("--instance all is only supported by ls and stats"))
+	}
+	instances, err := selectInstances(client, instance)
+	if err != nil {
+		fatalError(err)
+	}
+	return instances[0]
+}
+
+// forEachInstance calls fn for each instance.  With more than one, a failing instance
+// is logged and the rest still run.
+func forEachInstance(instances []string, fn func(i int, instance string) error) error {
+	if len(instances) == 1 {
+		return fn(0, instances[0])
+	}
+	numFailed := 0
+	for i, instance := range instances {
+		err := fn(i, instance)
+		if err != nil {
+			logErrorf("%s: %v\n", instance, err)
+			numFailed++
+		}
+	}
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d instances failed", numFailed, len(instances))
+	}
+	return nil
+}
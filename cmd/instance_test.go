@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstances(t *testing.T) {
+	viper.SetConfigType("toml")
+	err := viper.ReadConfig(strings.NewReader(`
+[qbit]
+server = "http://localhost:8080"
+username = "admin"
+[qbit.seedbox1]
+server = "http://seedbox1:8080"
+[qbit.racing]
+server = "http://racing:8080"
+password = "secret"
+[[qbit.rules]]
+name = "cleanup"
+`))
+	assert.NoError(t, err)
+	defer viper.ReadConfig(strings.NewReader(""))
+
+	assert.Equal(t, []string{"default", "racing", "seedbox1"}, instanceNames("qbit"))
+	assert.Equal(t, []string{"default"}, instanceNames("deluge"))
+
+	instances, err := selectInstances("qbit", "all")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "racing", "seedbox1"}, instances)
+	instances, err = selectInstances("qbit", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default"}, instances)
+	_, err = selectInstances("qbit", "rules")
+	assert.Error(t, err)
+
+	// settings missing from an instance section fall back to the flat section
+	assert.Equal(t, "http://racing:8080", viper.GetString(qbitInstanceKey("racing", "server")))
+	assert.Equal(t, "secret", viper.GetString(qbitInstanceKey("racing", "password")))
+	assert.Equal(t, "admin", viper.GetString(qbitInstanceKey("racing", "username")))
+	assert.Equal(t, "http://localhost:8080", viper.GetString(qbitInstanceKey("default", "server")))
+}
+
+func TestInstancePassword(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "qbit")
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("from-file\n"), 0600))
+	viper.SetConfigType("toml")
+	err := viper.ReadConfig(strings.NewReader(`
+[qbit]
+password = "flat"
+password_file = "` + filepath.ToSlash(passwordFile) + `"
+[qbit.racing]
+server = "http://racing:8080"
+password = "racing"
+[qbit.seedbox1]
+server = "http://seedbox1:8080"
+`))
+	assert.NoError(t, err)
+	defer viper.ReadConfig(strings.NewReader(""))
+	initEnv()
+	flags := qbitCmd.PersistentFlags()
+
+	password, err := instancePassword(flags, "qbit", "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", password)
+	password, err = instancePassword(flags, "qbit", "racing")
+	assert.NoError(t, err)
+	assert.Equal(t, "racing", password)
+	password, err = instancePassword(flags, "qbit", "seedbox1")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", password)
+
+	t.Setenv("TT_QBIT_SEEDBOX1_PASSWORD", "from-env")
+	password, err = instancePassword(flags, "qbit", "seedbox1")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", password)
+}
@@ -4,14 +4,229 @@ Copyright © 2025 Kenneth H. Cox
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
-var stdoutLogger = newLogger(os.Stdout)
-var stderrLogger = newLogger(os.Stderr)
+// LevelTrace is the level of messages logged only with -vv
+const LevelTrace = slog.LevelDebug - 4
 
-func newLogger(w io.Writer) *log.Logger {
-	return log.New(w, "", log.Ldate|log.Ltime|log.Lmicroseconds)
+var logFormats = []string{"text", "json"}
+
+var logLevel = new(slog.LevelVar)
+var logger = newLogger(os.Stdout, os.Stderr, "text", nil)
+
+// logs is the log session of this run of tt
+var logs = newLogSession(os.Stdout, os.Stderr)
+
+// LogOptions is what initLogging reads from flags or config
+type LogOptions struct {
+	File      string
+	Format    string
+	Quiet     bool
+	Verbosity int
+	Rotate    LogRotateOptions
+}
+
+// logSession sets up the logger for one run of tt and closes the log file on exit.
+// The writers, clock and exit function are replaced in tests.
+type logSession struct {
+	stdout io.Writer
+	stderr io.Writer
+	now    func() time.Time
+	exit   func(code int)
+
+	format string
+	file   io.WriteCloser
+}
+
+func newLogSession(stdout io.Writer, stderr io.Writer) *logSession {
+	return &logSession{stdout: stdout, stderr: stderr, now: time.Now, exit: os.Exit, format: "text"}
+}
+
+// start opens the log file if any and points the logger at it
+func (s *logSession) start(opts LogOptions) error {
+	if !slices.Contains(logFormats, opts.Format) {
+		return fmt.Errorf("unknown log format: %s (expected one of {%s})", opts.Format, strings.Join(logFormats, ", "))
+	}
+	s.format = opts.Format
+	setLogVerbosity(opts.Verbosity)
+
+	// handle quiet first; errors still go to stderr
+	if opts.Quiet {
+		s.setOutput(io.Discard, s.stderr)
+		return nil
+	}
+	s.setOutput(s.stdout, s.stderr)
+
+	// open the log file
+	if opts.File != "" {
+		file, err := openLogFile(opts.File, opts.Rotate)
+		if err != nil {
+			return fmt.Errorf("%s: %v", opts.File, err)
+		}
+		s.file = file
+		s.setOutput(file, file)
+	}
+	return nil
+}
+
+// setOutput replaces the logger with one writing to the given writers
+func (s *logSession) setOutput(stdout io.Writer, stderr io.Writer) {
+	logger = newLogger(stdout, stderr, s.format, s.now)
+}
+
+// finish closes the log file; it is safe to call more than once
+func (s *logSession) finish() {
+	if s.file == nil {
+		return
+	}
+	s.setOutput(s.stdout, s.stderr)
+	s.file.Close()
+	s.file = nil
+}
+
+// exitWith closes the log file and exits with the given code
+func (s *logSession) exitWith(code int) {
+	s.finish()
+	s.exit(code)
+}
+
+// openLogFile opens the log file for appending, rotating it if any limit is set
+func openLogFile(path string, opts LogRotateOptions) (io.WriteCloser, error) {
+	if opts.enabled() {
+		return openRotatingLogFile(path, opts)
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+}
+
+// newLogger returns a logger that writes errors to stderr and everything else to stdout,
+// in either the traditional text format or as JSON; now replaces the time of each record
+// if not nil
+func newLogger(stdout io.Writer, stderr io.Writer, format string, now func() time.Time) *slog.Logger {
+	return slog.New(&logHandler{
+		out: newLogFormatHandler(stdout, format),
+		err: newLogFormatHandler(stderr, format),
+		now: now,
+	})
+}
+
+// setLogVerbosity sets the level from the count of -v flags
+func setLogVerbosity(verbosity int) {
+	switch {
+	case verbosity > 1:
+		logLevel.Set(LevelTrace)
+	case verbosity > 0:
+		logLevel.Set(slog.LevelDebug)
+	default:
+		logLevel.Set(slog.LevelInfo)
+	}
+}
+
+func newLogFormatHandler(w io.Writer, format string) slog.Handler {
+	if format == "json" {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: LevelTrace,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.LevelKey && a.Value.Any() == LevelTrace {
+					a.Value = slog.StringValue("TRACE")
+				}
+				return a
+			},
+		})
+	}
+	return &textLogHandler{w: w, mu: &sync.Mutex{}}
+}
+
+// logHandler filters by logLevel and routes errors to a separate handler
+type logHandler struct {
+	out slog.Handler
+	err slog.Handler
+	now func() time.Time
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= logLevel.Level() || level >= slog.LevelError
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.now != nil {
+		r.Time = h.now()
+	}
+	if r.Level >= slog.LevelError {
+		return h.err.Handle(ctx, r)
+	}
+	return h.out.Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{out: h.out.WithAttrs(attrs), err: h.err.WithAttrs(attrs), now: h.now}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{out: h.out.WithGroup(name), err: h.err.WithGroup(name), now: h.now}
+}
+
+// textLogHandler writes messages the way log.Logger did with Ldate|Ltime|Lmicroseconds,
+// so existing greps of log files keep working.  Attributes are left to the JSON format,
+// since text messages already include the hash and attempt.
+type textLogHandler struct {
+	w  io.Writer
+	mu *sync.Mutex
+}
+
+func (h *textLogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *textLogHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	buf.WriteString(t.Format("2006/01/02 15:04:05.000000 "))
+	buf.WriteString(r.Message)
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *textLogHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *textLogHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// logAttrsf logs a formatted message at level, with attributes for the JSON format
+func logAttrsf(level slog.Level, attrs []any, format string, args ...any) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	logger.Log(ctx, level, msg, attrs...)
+}
+
+// torrentAttrs returns the attributes for a message about one torrent;
+// attempt is e.g. "try 3", "extra 1" or "final"
+func torrentAttrs(hash string, attempt string) []any {
+	attrs := []any{slog.String("hash", hash)}
+	if attempt != "" {
+		attrs = append(attrs, slog.String("attempt", attempt))
+	}
+	return attrs
 }
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Text(t *testing.T) {
+	var out, errOut bytes.Buffer
+	logger := newLogger(&out, &errOut, "text", nil)
+	logLevel.Set(slog.LevelInfo)
+
+	logger.Info("abc: try 1: reannounce requested", torrentAttrs("abc", "try 1")...)
+	logger.Debug("hidden")
+	logger.Error("failed")
+
+	assert.Regexp(t, `^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d\.\d{6} abc: try 1: reannounce requested\n$`, out.String())
+	assert.Regexp(t, `^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d\.\d{6} failed\n$`, errOut.String())
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var out bytes.Buffer
+	logger := newLogger(&out, &out, "json", nil)
+	setLogVerbosity(2)
+	defer setLogVerbosity(0)
+
+	logger.Log(t.Context(), LevelTrace, "abc: try 2: sleep 7", torrentAttrs("abc", "try 2")...)
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &m))
+	assert.Equal(t, "TRACE", m["level"])
+	assert.Equal(t, "abc", m["hash"])
+	assert.Equal(t, "try 2", m["attempt"])
+}
+
+// exitCode is what a test logSession panics with instead of exiting
+type exitCode int
+
+// newTestLogSession returns a log session with a fixed clock, that panics with exitCode
+// instead of exiting, and restores the global logs when the test ends
+func newTestLogSession(t *testing.T) (*logSession, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	s := newLogSession(&stdout, &stderr)
+	s.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local) }
+	s.exit = func(code int) { panic(exitCode(code)) }
+
+	saved := logs
+	logs = s
+	t.Cleanup(func() {
+		logs.finish()
+		logs = saved
+		logs.setOutput(os.Stdout, os.Stderr)
+		setLogVerbosity(0)
+	})
+	return s, &stdout, &stderr
+}
+
+// catchExit runs fn and returns the code it exited with, or -1 if it returned
+func catchExit(fn func()) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			code = int(r.(exitCode))
+		}
+	}()
+	fn()
+	return -1
+}
+
+func TestLogSession_Quiet(t *testing.T) {
+	s, stdout, stderr := newTestLogSession(t)
+	assert.NoError(t, s.start(LogOptions{Format: "text", Quiet: true}))
+
+	logf("hello\n")
+	logErrorf("oops\n")
+
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, "2025/03/01 12:00:00.000000 oops\n", stderr.String())
+}
+
+func TestLogSession_Verbose(t *testing.T) {
+	s, stdout, _ := newTestLogSession(t)
+	assert.NoError(t, s.start(LogOptions{Format: "text", Verbosity: 1}))
+
+	vLogf("debug\n")
+	vvLogf("trace\n")
+
+	assert.Equal(t, "2025/03/01 12:00:00.000000 debug\n", stdout.String())
+}
+
+func TestLogSession_BadFormat(t *testing.T) {
+	s, _, _ := newTestLogSession(t)
+	assert.EqualError(t, s.start(LogOptions{Format: "xml"}), "unknown log format: xml (expected one of {text, json})")
+}
+
+func TestLogSession_FatalErrorClosesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tt.log")
+	s, stdout, stderr := newTestLogSession(t)
+	assert.NoError(t, s.start(LogOptions{File: path, Format: "text"}))
+	file := s.file
+
+	logf("starting\n")
+	code := catchExit(func() { fatalError(errors.New("boom")) })
+
+	assert.Equal(t, 1, code)
+	assert.Nil(t, s.file)
+	assert.Error(t, file.Close(), "log file should already be closed")
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025/03/01 12:00:00.000000 starting\n2025/03/01 12:00:00.000000 boom\n", string(data))
+}
+
+// TestRootCmd_LogFile runs tt end to end, once successfully and once with a fatal error,
+// and checks that the log file gets every line and is closed both times
+func TestRootCmd_LogFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "tt.log")
+	configPath := filepath.Join(dir, "tt.toml")
+	outPath := filepath.Join(dir, "out.toml")
+	assert.NoError(t, os.WriteFile(configPath, nil, 0644))
+	s, stdout, _ := newTestLogSession(t)
+
+	args := []string{"--config", configPath, "--log-file", logPath, "config", "init", "--output", outPath}
+	assert.Equal(t, 0, runTT(t, args...))
+	assert.Nil(t, s.file)
+
+	// the second time the output exists
+	assert.Equal(t, 1, runTT(t, args...))
+	assert.Nil(t, s.file)
+
+	assert.Empty(t, stdout.String())
+	data, err := os.ReadFile(logPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Wrote "+outPath+"\n")
+	assert.Contains(t, string(data), outPath+" already exists; use --force to overwrite it\n")
+}
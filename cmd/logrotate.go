@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogRotateOptions controls when the log file is rotated and how many old segments are kept
+type LogRotateOptions struct {
+	MaxSize    int64         // rotate when the file would grow past this many bytes, 0 for no limit
+	MaxAge     time.Duration // rotate when the segment is older than this, 0 for no limit
+	MaxBackups int           // number of gzipped segments to keep, 0 to keep all
+}
+
+func (o LogRotateOptions) enabled() bool {
+	return o.MaxSize > 0 || o.MaxAge > 0
+}
+
+// rotatingLogFile appends to a log file that is shared by every tt process started by
+// qBittorrent or Deluge, rotating it when it gets too big or too old.
+//
+// Processes coordinate through a lock on PATH.lock: each write holds a shared lock, and
+// rotation holds an exclusive lock while it moves the file aside.  A writer that finds
+// the file was renamed under it reopens PATH before writing, so no line lands in a
+// segment that is being compressed.  On Windows the file is copied and truncated
+// instead of renamed, because it cannot be renamed while other processes have it
+// open.  The mtime of PATH.lock records when the segment started.
+type rotatingLogFile struct {
+	path string
+	opts LogRotateOptions
+	now  func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	lock *os.File
+}
+
+const logRotateTimeFormat = "20060102-150405.000000"
+
+func openRotatingLogFile(path string, opts LogRotateOptions) (*rotatingLogFile, error) {
+	f := &rotatingLogFile{path: path, opts: opts, now: time.Now}
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+	f.lock = lock
+	if err := f.open(); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingLogFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.file = file
+	return nil
+}
+
+func (f *rotatingLogFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.needsRotation(int64(len(p))) {
+		if err := f.rotate(int64(len(p))); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: rotate: %v\n", f.path, err)
+		}
+	}
+
+	if err := lockFile(f.lock, false); err != nil {
+		return 0, err
+	}
+	defer unlockFile(f.lock)
+	if !f.isCurrent() {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	return f.file.Write(p)
+}
+
+func (f *rotatingLogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lock.Close()
+	return f.file.Close()
+}
+
+// isCurrent returns true if our file is still the one at path
+func (f *rotatingLogFile) isCurrent() bool {
+	fi, err := f.file.Stat()
+	if err != nil {
+		return false
+	}
+	pi, err := os.Stat(f.path)
+	return err == nil && os.SameFile(fi, pi)
+}
+
+// needsRotation checks the file at path, which another process may have rotated already
+func (f *rotatingLogFile) needsRotation(n int64) bool {
+	fi, err := os.Stat(f.path)
+	if err != nil || fi.Size() == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && fi.Size()+n > f.opts.MaxSize {
+		return true
+	}
+	if f.opts.MaxAge > 0 {
+		li, err := f.lock.Stat()
+		if err == nil && f.now().Sub(li.ModTime()) >= f.opts.MaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// rotate moves the log file aside under the exclusive lock, then compresses it and
+// removes old segments after releasing the lock
+func (f *rotatingLogFile) rotate(n int64) error {
+	if err := lockFile(f.lock, true); err != nil {
+		return err
+	}
+	if !f.needsRotation(n) {
+		// another process got here first
+		unlockFile(f.lock)
+		return nil
+	}
+	now := f.now()
+	segment := f.path + "." + now.Format(logRotateTimeFormat)
+	// close our handle first; Windows cannot move a file that we hold open
+	f.file.Close()
+	f.file = nil
+	err := moveSegment(f.path, segment)
+	if err == nil {
+		err = os.Chtimes(f.lock.Name(), now, now)
+	}
+	if oerr := f.open(); err == nil {
+		err = oerr
+	}
+	unlockFile(f.lock)
+	if err != nil {
+		return err
+	}
+
+	if err := gzipFile(segment); err != nil {
+		return err
+	}
+	return f.removeOldSegments()
+}
+
+// segments returns the rotated segments, oldest first
+func (f *rotatingLogFile) segments() ([]string, error) {
+	matches, err := filepath.Glob(f.path + ".*.gz")
+	if err != nil {
+		return nil, err
+	}
+	var segments []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, f.path+"."), ".gz")
+		if _, err := time.Parse(logRotateTimeFormat, stamp); err == nil {
+			segments = append(segments, match)
+		}
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
+
+func (f *rotatingLogFile) removeOldSegments() error {
+	if f.opts.MaxBackups <= 0 {
+		return nil
+	}
+	segments, err := f.segments()
+	if err != nil {
+		return err
+	}
+	for len(segments) > f.opts.MaxBackups {
+		if err := os.Remove(segments[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		segments = segments[1:]
+	}
+	return nil
+}
+
+// gzipFile compresses path to path.gz and removes path
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmpPath := path + ".gz.tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(path)
+	_, err = io.Copy(zw, in)
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path+".gz")
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readGzipFile(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestRotatingLogFile_MaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tt.log")
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	opts := LogRotateOptions{MaxSize: 10, MaxBackups: 1}
+
+	// two writers, as if from two processes
+	a, err := openRotatingLogFile(path, opts)
+	assert.NoError(t, err)
+	defer a.Close()
+	b, err := openRotatingLogFile(path, opts)
+	assert.NoError(t, err)
+	defer b.Close()
+	for _, f := range []*rotatingLogFile{a, b} {
+		f.now = func() time.Time { now = now.Add(time.Second); return now }
+	}
+
+	for _, line := range []string{"a1\n", "b1\n", "a2\n", "b2\n", "a3\n", "b3\n", "a4\n", "b4\n"} {
+		f := a
+		if line[0] == 'b' {
+			f = b
+		}
+		_, err := f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a4\nb4\n", string(data))
+
+	segments, err := a.segments()
+	assert.NoError(t, err)
+	assert.Len(t, segments, 1)
+	assert.Equal(t, "b2\na3\nb3\n", readGzipFile(t, segments[0]))
+}
+
+func TestRotatingLogFile_MaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tt.log")
+	f, err := openRotatingLogFile(path, LogRotateOptions{MaxAge: time.Hour})
+	assert.NoError(t, err)
+	defer f.Close()
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	f.Write([]byte("old\n"))
+	now = now.Add(2 * time.Hour)
+	f.Write([]byte("new\n"))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+	segments, err := f.segments()
+	assert.NoError(t, err)
+	assert.Len(t, segments, 1)
+	assert.Equal(t, "old\n", readGzipFile(t, segments[0]))
+}
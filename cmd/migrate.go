@@ -0,0 +1,352 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+type MigrateOptions struct {
+	Filter    string
+	Paused    bool
+	SkipCheck bool
+	Remove    bool
+	DryRun    bool
+}
+
+// migrateTorrent is the part of a torrent that is carried over to the other client
+type migrateTorrent struct {
+	Hash     string
+	Name     string
+	SavePath string // host path
+	Category string // qbit category or deluge label
+}
+
+// migrateClient is what migrate needs from the source and target clients
+type migrateClient interface {
+	list(ctx context.Context, hashes []string) ([]migrateTorrent, error)
+	has(ctx context.Context, hash string) (bool, error)
+	export(ctx context.Context, hash string) ([]byte, error)
+	add(ctx context.Context, t migrateTorrent, data []byte, opts MigrateOptions) error
+	remove(ctx context.Context, hash string) error
+	close()
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().String("from", "deluge", "Source client, \"deluge\" or \"qbit\"")
+	migrateCmd.Flags().String("to", "qbit", "Target client, \"qbit\" or \"deluge\"")
+	migrateCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	migrateCmd.Flags().BoolP("paused", "p", false, "Add to the target in the paused state")
+	migrateCmd.Flags().Bool("skip-check", false, "Skip the hash check in the target")
+	migrateCmd.Flags().Bool("remove", false, "Remove each torrent from the source (keeping its files) once it is in the target")
+	migrateCmd.Flags().BoolP("dry-run", "n", false, "Print what would be migrated")
+	viper.BindPFlag("migrate.from", migrateCmd.Flags().Lookup("from"))
+	viper.BindPFlag("migrate.to", migrateCmd.Flags().Lookup("to"))
+	viper.BindPFlag("migrate.filter", migrateCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("migrate.paused", migrateCmd.Flags().Lookup("paused"))
+	viper.BindPFlag("migrate.skip-check", migrateCmd.Flags().Lookup("skip-check"))
+	viper.BindPFlag("migrate.remove", migrateCmd.Flags().Lookup("remove"))
+	viper.BindPFlag("migrate.dry-run", migrateCmd.Flags().Lookup("dry-run"))
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [hash]...",
+	Short: "Migrate torrents between deluge and qBittorrent",
+	Long: `Migrate torrents from one client to the other, keeping the save path
+(after [pathmap] mapping) and the category or label.  Each torrent is verified
+to be in the target before it is removed from the source with --remove; the
+files are never deleted.  Torrents already in the target are skipped, so it is
+safe to run again.
+
+Exporting from deluge reads .torrent files from deluge.state-dir.`,
+	Run: migrateCmdRun,
+}
+
+func migrateCmdRun(cmd *cobra.Command, args []string) {
+	from := viper.GetString("migrate.from")
+	to := viper.GetString("migrate.to")
+	opts := MigrateOptions{
+		Filter:    viper.GetString("migrate.filter"),
+		Paused:    viper.GetBool("migrate.paused"),
+		SkipCheck: viper.GetBool("migrate.skip-check"),
+		Remove:    viper.GetBool("migrate.remove"),
+		DryRun:    viper.GetBool("migrate.dry-run"),
+	}
+	if from == to {
+		fatalError(fmt.Errorf("--from and --to are both %s", from))
+	}
+
+	ctx := context.Background()
+	source, err := migrateConnect(ctx, from)
+	if err != nil {
+		fatalError(err)
+	}
+	defer source.close()
+	target, err := migrateConnect(ctx, to)
+	if err != nil {
+		fatalError(err)
+	}
+	defer target.close()
+
+	err = migrate(ctx, source, target, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+// migrateConnect connects to the named client
+func migrateConnect(ctx context.Context, name string) (migrateClient, error) {
+	switch name {
+	case "qbit":
+		client := qbitCreateClient()
+		err := client.LoginCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &qbitMigrateClient{client: client}, nil
+	case "deluge":
+		client := delugeCreateV2Client()
+		err := client.Connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &delugeMigrateClient{client: client, stateDir: viper.GetString("deluge.state-dir")}, nil
+	default:
+		return nil, fmt.Errorf("unknown client \"%s\", expected \"qbit\" or \"deluge\"", name)
+	}
+}
+
+func migrate(ctx context.Context, source migrateClient, target migrateClient, hashes []string, opts MigrateOptions) error {
+	torrents, err := source.list(ctx, hashes)
+	if err != nil {
+		return err
+	}
+
+	numMigrated, numFailed := 0, 0
+	for _, t := range torrents {
+		if !matchesFilter(t.Name, opts.Filter) {
+			continue
+		}
+		err := migrateOne(ctx, source, target, t, opts)
+		if err != nil {
+			logErrorf("%s: %v\n", t.Hash, err)
+			numFailed++
+			continue
+		}
+		numMigrated++
+	}
+
+	vLogf("migrated %d torrents\n", numMigrated)
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d torrents could not be migrated", numFailed, numMigrated+numFailed)
+	}
+	return nil
+}
+
+func migrateOne(ctx context.Context, source migrateClient, target migrateClient, t migrateTorrent, opts MigrateOptions) error {
+	found, err := target.has(ctx, t.Hash)
+	if err != nil {
+		return err
+	}
+	if found {
+		logf("%s: already in target \"%s\"\n", t.Hash, t.Name)
+	} else {
+		logf("%s: migrate \"%s\" to \"%s\" category \"%s\"\n", t.Hash, t.Name, t.SavePath, t.Category)
+		if opts.DryRun {
+			return nil
+		}
+		data, err := source.export(ctx, t.Hash)
+		if err != nil {
+			return fmt.Errorf("error exporting: %v", err)
+		}
+		err = target.add(ctx, t, data, opts)
+		if err != nil {
+			return fmt.Errorf("error adding: %v", err)
+		}
+		err = migrateWaitForTorrent(ctx, target, t.Hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Remove {
+		logf("%s: remove from source \"%s\"\n", t.Hash, t.Name)
+		if opts.DryRun {
+			return nil
+		}
+		return source.remove(ctx, t.Hash)
+	}
+	return nil
+}
+
+// migrateWaitForTorrent verifies that a torrent shows up in the target after adding it
+func migrateWaitForTorrent(ctx context.Context, target migrateClient, hash string) error {
+	found, err := waitForAdded(ctx, func(ctx context.Context) (bool, error) {
+		return target.has(ctx, hash)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("torrent did not show up in the target after adding")
+	}
+	return nil
+}
+
+// qbitMigrateClient adapts a qbit client to migrateClient
+type qbitMigrateClient struct {
+	client     internal.QbitClientInterface
+	categories map[string]qbittorrent.Category
+}
+
+func (c *qbitMigrateClient) list(ctx context.Context, hashes []string) ([]migrateTorrent, error) {
+	torrents, err := qbitSelectTorrents(ctx, c.client, hashes, RmOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var result []migrateTorrent
+	for _, t := range torrents {
+		result = append(result, migrateTorrent{Hash: t.Hash, Name: t.Name, SavePath: hostPath(t.SavePath), Category: t.Category})
+	}
+	return result, nil
+}
+
+func (c *qbitMigrateClient) has(ctx context.Context, hash string) (bool, error) {
+	torrents, err := c.client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{hash}})
+	return len(torrents) > 0, err
+}
+
+func (c *qbitMigrateClient) export(ctx context.Context, hash string) ([]byte, error) {
+	return c.client.ExportTorrentCtx(ctx, hash)
+}
+
+func (c *qbitMigrateClient) add(ctx context.Context, t migrateTorrent, data []byte, opts MigrateOptions) error {
+	// create the category if needed
+	if t.Category != "" {
+		if c.categories == nil {
+			categories, err := c.client.GetCategoriesCtx(ctx)
+			if err != nil {
+				return err
+			}
+			c.categories = categories
+		}
+		if _, ok := c.categories[t.Category]; !ok {
+			logf("creating category \"%s\"\n", t.Category)
+			err := c.client.CreateCategoryCtx(ctx, t.Category, "")
+			if err != nil {
+				return err
+			}
+			c.categories[t.Category] = qbittorrent.Category{Name: t.Category}
+		}
+	}
+
+	src := addSource{Data: data, Hash: t.Hash}
+	return qbitAddSource(ctx, c.client, src, AddOptions{
+		SavePath:  t.SavePath,
+		Category:  t.Category,
+		Paused:    opts.Paused,
+		SkipCheck: opts.SkipCheck,
+	})
+}
+
+func (c *qbitMigrateClient) remove(ctx context.Context, hash string) error {
+	return c.client.DeleteTorrentsCtx(ctx, []string{hash}, false)
+}
+
+func (c *qbitMigrateClient) close() {}
+
+// delugeMigrateClient adapts a deluge client to migrateClient
+type delugeMigrateClient struct {
+	client   deluge.DelugeClient
+	stateDir string
+	labels   []string
+}
+
+func (c *delugeMigrateClient) list(ctx context.Context, hashes []string) ([]migrateTorrent, error) {
+	torrentsStatus, err := delugeGetTorrentsStatus(ctx, c.client, hashes)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := delugeGetLabels(ctx, c.client, hashes)
+	if err != nil {
+		vLogf("not migrating labels: %v\n", err)
+	}
+	var result []migrateTorrent
+	for _, hash := range delugeSortedKeys(torrentsStatus) {
+		ts := torrentsStatus[hash]
+		result = append(result, migrateTorrent{Hash: hash, Name: ts.Name, SavePath: hostPath(ts.SavePath), Category: labels[hash]})
+	}
+	return result, nil
+}
+
+func (c *delugeMigrateClient) has(ctx context.Context, hash string) (bool, error) {
+	torrentsStatus, err := c.client.TorrentsStatus(ctx, deluge.StateUnspecified, []string{hash})
+	if err != nil {
+		return false, err
+	}
+	_, ok := torrentsStatus[hash]
+	return ok, nil
+}
+
+func (c *delugeMigrateClient) export(ctx context.Context, hash string) ([]byte, error) {
+	if c.stateDir == "" {
+		return nil, fmt.Errorf("no deluge state directory; set deluge.state-dir")
+	}
+	return delugeReadStateTorrent(c.stateDir, hash)
+}
+
+func (c *delugeMigrateClient) add(ctx context.Context, t migrateTorrent, data []byte, opts MigrateOptions) error {
+	src := addSource{Path: t.Name + ".torrent", Data: data, Hash: t.Hash}
+	hash, err := delugeAddSource(ctx, c.client, src, AddOptions{
+		SavePath:  t.SavePath,
+		Paused:    opts.Paused,
+		SkipCheck: opts.SkipCheck,
+	})
+	if err != nil || t.Category == "" {
+		return err
+	}
+
+	// deluge labels must be lowercase; create the label if needed
+	label := strings.ToLower(t.Category)
+	plugin, err := delugeLabelPlugin(ctx, c.client)
+	if err != nil {
+		return err
+	}
+	if c.labels == nil {
+		c.labels, err = plugin.GetLabels(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if !slices.Contains(c.labels, label) {
+		logf("creating label \"%s\"\n", label)
+		err = plugin.AddLabel(ctx, label)
+		if err != nil {
+			return err
+		}
+		c.labels = append(c.labels, label)
+	}
+	return plugin.SetTorrentLabel(ctx, hash, label)
+}
+
+func (c *delugeMigrateClient) remove(ctx context.Context, hash string) error {
+	_, err := c.client.RemoveTorrent(ctx, hash, false)
+	return err
+}
+
+func (c *delugeMigrateClient) close() {
+	c.client.Close()
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestMigrate_DelugeToQbit(t *testing.T) {
+	hash := "8694d6007ae15e276cbda435c410f6e2b6bd6f76"
+	stateDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(stateDir, hash+".torrent"), testTorrent, 0644))
+
+	ctx := context.Background()
+	delugeClient := mocks.NewDelugeMockClient()
+	var all []string
+	delugeClient.On("TorrentsStatus", ctx, deluge.StateUnspecified, all).Return(map[string]*deluge.TorrentStatus{
+		hash: {Hash: hash, Name: "a.txt", SavePath: "/downloads"},
+	}, nil)
+	delugeClient.On("RemoveTorrent", ctx, hash, false).Return(true, nil)
+
+	qbitClient := mocks.NewQbitMockClient()
+	filter := qbittorrent.TorrentFilterOptions{Hashes: []string{hash}}
+	qbitClient.On("GetTorrentsCtx", ctx, filter).Return([]qbittorrent.Torrent{}, nil).Once()
+	qbitClient.On("GetTorrentsCtx", ctx, filter).Return([]qbittorrent.Torrent{{Hash: hash}}, nil)
+	qbitClient.On("AddTorrentFromMemoryCtx", ctx, testTorrent, mock.MatchedBy(func(o map[string]string) bool {
+		return o["savepath"] == "/downloads" && o["skip_checking"] == "true"
+	})).Return(nil)
+
+	source := &delugeMigrateClient{client: delugeClient, stateDir: stateDir}
+	target := &qbitMigrateClient{client: qbitClient}
+	err := migrate(ctx, source, target, nil, MigrateOptions{SkipCheck: true, Remove: true})
+	assert.NoError(t, err)
+
+	delugeClient.AssertExpectations(t)
+	qbitClient.AssertExpectations(t)
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	ctx := context.Background()
+	qbitClient := mocks.NewQbitMockClient()
+	qbitClient.On("GetTorrentsCtx", ctx, mock.Anything).Return([]qbittorrent.Torrent{{Hash: "a", Name: "A"}}, nil)
+	delugeClient := mocks.NewDelugeMockClient()
+	delugeClient.On("TorrentsStatus", ctx, deluge.StateUnspecified, []string{"a"}).Return(map[string]*deluge.TorrentStatus{}, nil)
+
+	source := &qbitMigrateClient{client: qbitClient}
+	target := &delugeMigrateClient{client: delugeClient}
+	err := migrate(ctx, source, target, nil, MigrateOptions{DryRun: true, Remove: true})
+	assert.NoError(t, err)
+
+	delugeClient.AssertExpectations(t)
+	qbitClient.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type MoveOptions struct {
+	Wait    bool
+	Timeout int // seconds
+}
+
+// moveStatus is what --wait needs to know about a torrent being moved
+type moveStatus struct {
+	Moving   bool
+	Error    string
+	SavePath string
+	Files    []string // relative to SavePath, only needed once Moving is false
+}
+
+// movePollInterval is how often --wait checks on the torrent
+var movePollInterval = 2 * time.Second
+
+// waitForMove polls getStatus until the torrent has finished moving to dest,
+// then checks that all of its files exist there
+func waitForMove(hash string, dest string, timeout time.Duration, getStatus func() (moveStatus, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := getStatus()
+		if err != nil {
+			return err
+		}
+		if status.Error != "" {
+			return fmt.Errorf("%s: move failed: %s", hash, status.Error)
+		}
+		if !status.Moving && samePath(status.SavePath, dest) {
+			vLogf("%s: moved to \"%s\"\n", hash, status.SavePath)
+			return verifyFilesExist(hash, dest, status.Files)
+		}
+		vvLogf("%s: waiting for move: moving=%v save_path=\"%s\"\n", hash, status.Moving, status.SavePath)
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s: timed out waiting for move to \"%s\"", hash, dest)
+		}
+		time.Sleep(movePollInterval)
+	}
+}
+
+// verifyFilesExist returns an error if any of files is missing from dir
+func verifyFilesExist(hash string, dir string, files []string) error {
+	var missing []string
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			vLogf("%s: %v\n", hash, err)
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s: %d of %d files missing from \"%s\", e.g. \"%s\"", hash, len(missing), len(files), dir, missing[0])
+	}
+	logf("%s: verified %d files in \"%s\"\n", hash, len(files), dir)
+	return nil
+}
+
+// samePath compares two paths ignoring trailing separators
+func samePath(a string, b string) bool {
+	return strings.TrimRight(a, `/\`) == strings.TrimRight(b, `/\`)
+}
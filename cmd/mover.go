@@ -0,0 +1,416 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// MoverRule selects finished torrents in one client and moves them to another directory, e.g.
+//
+//	[[mover.rules]]
+//	name = "tv"
+//	client = "qbit"
+//	category = "tv"
+//	from = "/mnt/ssd/tv"
+//	to = "/mnt/hdd/tv"
+//	min_completed_age = "2d"
+//	min_free_space = "100GiB"
+type MoverRule struct {
+	Name            string `mapstructure:"name"`
+	Client          string `mapstructure:"client"`   // "qbit" or "deluge"
+	Category        string `mapstructure:"category"` // qbit category or deluge label
+	Filter          string `mapstructure:"filter"`
+	From            string `mapstructure:"from"`
+	To              string `mapstructure:"to"`
+	MinCompletedAge string `mapstructure:"min_completed_age"`
+	MinFreeSpace    string `mapstructure:"min_free_space"`
+
+	minCompletedAge time.Duration
+	minFreeSpace    int64
+}
+
+type MoverOptions struct {
+	DryRun      bool
+	Concurrency int
+	Timeout     int // seconds, per torrent
+}
+
+// moverTorrent is the part of a torrent the mover cares about, from either client
+type moverTorrent struct {
+	Hash        string
+	Name        string
+	Category    string
+	SavePath    string
+	Dest        string // where SavePath maps to under the rule's "to" path
+	Size        int64
+	CompletedOn int64 // unix time, 0 if not completed
+}
+
+func init() {
+	rootCmd.AddCommand(moverCmd)
+
+	moverCmd.Flags().StringSliceP("rule", "r", []string{"all"}, "Run the named rules from tt.toml")
+	moverCmd.Flags().BoolP("dry-run", "n", false, "Print the plan without moving anything")
+	moverCmd.Flags().IntP("concurrency", "j", 1, "Number of torrents to move at the same time")
+	moverCmd.Flags().IntP("timeout", "t", 60*60, "Maximum time to wait for each move in seconds")
+	viper.BindPFlag("mover.rule", moverCmd.Flags().Lookup("rule"))
+	viper.BindPFlag("mover.dry-run", moverCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("mover.concurrency", moverCmd.Flags().Lookup("concurrency"))
+	viper.BindPFlag("mover.timeout", moverCmd.Flags().Lookup("timeout"))
+}
+
+var moverCmd = &cobra.Command{
+	Use:   "mover",
+	Short: "Move finished torrents between storage tiers",
+	Long: `Move finished torrents to another directory according to rules in tt.toml, e.g.
+
+  [[mover.rules]]
+  name = "tv"
+  client = "qbit"             # or "deluge"
+  category = "tv"             # qbit category or deluge label
+  from = "/mnt/ssd/tv"        # only torrents saved under this path
+  to = "/mnt/hdd/tv"
+  min_completed_age = "2d"
+  min_free_space = "100GiB"   # leave at least this much free in "to"
+
+Torrents keep their layout under "from", so with the rule above a torrent saved in
+/mnt/ssd/tv/Show is moved to /mnt/hdd/tv/Show.  Without "from", every torrent is
+moved to "to" itself.
+
+Torrents are moved oldest first.  Up to --concurrency moves run at once (one by
+default), and each move is waited for and verified before its slot is given to
+the next torrent.  Check the plan first with:
+
+  tt mover --dry-run
+`,
+	Run: moverCmdRun,
+}
+
+func moverCmdRun(cmd *cobra.Command, args []string) {
+	// check flags
+	rules, err := moverLoadRules(viper.GetStringSlice("mover.rule"))
+	if err != nil {
+		fatalError(err)
+	}
+	opts := MoverOptions{
+		DryRun:      viper.GetBool("mover.dry-run"),
+		Concurrency: max(viper.GetInt("mover.concurrency"), 1),
+		Timeout:     viper.GetInt("mover.timeout"),
+	}
+
+	// run each rule in turn
+	var lastError error
+	for _, rule := range rules {
+		err = moverRun(context.Background(), rule, opts)
+		if err != nil {
+			logErrorf("%s: %v\n", rule.Name, err)
+			lastError = err
+		}
+	}
+	if lastError != nil {
+		exitWithFinalizers(1)
+	}
+}
+
+// moverLoadRules returns the rules from the config with the given names, or all of them for "all"
+func moverLoadRules(names []string) ([]MoverRule, error) {
+	var rules []MoverRule
+	err := viper.UnmarshalKey("mover.rules", &rules)
+	if err != nil {
+		return nil, fmt.Errorf("mover.rules: %v", err)
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no [[mover.rules]] in config")
+	}
+
+	var selected []MoverRule
+	for _, name := range names {
+		found := false
+		for _, rule := range rules {
+			if name == "all" || rule.Name == name {
+				selected = append(selected, rule)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: rule not found in config", name)
+		}
+	}
+
+	for i := range selected {
+		err = selected[i].validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+// validate checks the rule and parses the duration and size strings
+func (r *MoverRule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("mover rule is missing a name")
+	}
+	if r.Client != "qbit" && r.Client != "deluge" {
+		return fmt.Errorf("%s: client must be \"qbit\" or \"deluge\"", r.Name)
+	}
+	if r.To == "" {
+		return fmt.Errorf("%s: rule needs a \"to\" path", r.Name)
+	}
+	if r.MinCompletedAge != "" {
+		d, err := parseDuration(r.MinCompletedAge)
+		if err != nil {
+			return fmt.Errorf("%s: min_completed_age: %v", r.Name, err)
+		}
+		r.minCompletedAge = d
+	}
+	if r.MinFreeSpace != "" {
+		n, err := parseBytes(r.MinFreeSpace)
+		if err != nil {
+			return fmt.Errorf("%s: min_free_space: %v", r.Name, err)
+		}
+		r.minFreeSpace = n
+	}
+	return nil
+}
+
+func moverRun(ctx context.Context, rule MoverRule, opts MoverOptions) error {
+	// list torrents
+	var torrents []moverTorrent
+	var err error
+	if rule.Client == "qbit" {
+		torrents, err = moverListQbit(ctx, rule)
+	} else {
+		torrents, err = moverListDeluge(ctx, rule)
+	}
+	if err != nil {
+		return err
+	}
+
+	// make a plan, checking free space where each torrent will end up
+	freeSpace := func(dest string) (int64, error) {
+		return diskFreeSpace(existingParent(dest))
+	}
+	plan := moverPlan(rule, torrents, freeSpace, time.Now())
+	vLogf("%s: %d of %d torrents to move\n", rule.Name, len(plan), len(torrents))
+	if opts.DryRun {
+		moverPrintPlan(rule, plan)
+		return nil
+	}
+
+	return moverExecute(ctx, rule, plan, opts)
+}
+
+// moverPlan returns the torrents to move, oldest first, that fit in the free space at
+// their destinations.  Destinations are assumed to share a disk, so the space taken by
+// earlier torrents in the plan counts against every later one.
+func moverPlan(rule MoverRule, torrents []moverTorrent, freeSpace func(dest string) (int64, error), now time.Time) []moverTorrent {
+	var candidates []moverTorrent
+	for _, t := range torrents {
+		if t.CompletedOn <= 0 {
+			continue
+		}
+		if rule.Category != "" && t.Category != rule.Category {
+			continue
+		}
+		if !matchesFilter(t.Name, rule.Filter) {
+			continue
+		}
+		if rule.From != "" && !isSubPath(rule.From, t.SavePath) {
+			continue
+		}
+		t.Dest = moverDest(rule, t.SavePath)
+		if samePath(t.SavePath, t.Dest) {
+			continue
+		}
+		if now.Sub(time.Unix(t.CompletedOn, 0)) < rule.minCompletedAge {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].CompletedOn < candidates[j].CompletedOn })
+
+	var plan []moverTorrent
+	var planned int64
+	for _, t := range candidates {
+		free, err := freeSpace(t.Dest)
+		if err != nil {
+			logErrorf("%s: skipping, %s: %v\n", t.Hash, t.Dest, err)
+			continue
+		}
+		vvLogf("%s: %s free in \"%s\"\n", t.Hash, humanizeBytes(free), t.Dest)
+		if free-planned-t.Size < rule.minFreeSpace {
+			vLogf("%s: skipping, not enough space in \"%s\" for %s\n", t.Hash, t.Dest, humanizeBytes(t.Size))
+			continue
+		}
+		planned += t.Size
+		plan = append(plan, t)
+	}
+	return plan
+}
+
+// moverDest returns where a torrent saved in savePath goes: the same place relative to
+// rule.To as savePath is to rule.From
+func moverDest(rule MoverRule, savePath string) string {
+	if rule.From == "" {
+		return rule.To
+	}
+	rest, ok := cutPathPrefix(savePath, rule.From)
+	if !ok {
+		return rule.To
+	}
+	return filepath.Join(rule.To, rest)
+}
+
+// existingParent returns path or its closest ancestor that exists, since the move
+// creates missing directories
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// moverPrintPlan prints the plan as CSV
+func moverPrintPlan(rule MoverRule, plan []moverTorrent) {
+	fmt.Printf("rule,hash,size,from,to,name\n")
+	for _, t := range plan {
+		fmt.Printf("%s,%s,%s,%s,%s,%s\n", rule.Name, t.Hash, humanizeBytes(t.Size), t.SavePath, t.Dest, t.Name)
+	}
+}
+
+// moverExecute moves the torrents in the plan, opts.Concurrency at a time
+func moverExecute(ctx context.Context, rule MoverRule, plan []moverTorrent, opts MoverOptions) error {
+	jobs := make(chan moverTorrent)
+	var mu sync.Mutex
+	var failed int
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				// each worker uses its own connection; deluge clients are not safe to share
+				var err error
+				moveOpts := MoveOptions{Wait: true, Timeout: opts.Timeout}
+				if rule.Client == "qbit" {
+					err = qbitMove(ctx, qbitCreateClient(), t.Hash, t.Dest, moveOpts)
+				} else {
+					err = delugeMove(ctx, delugeCreateV2Client(), t.Hash, t.Dest, moveOpts)
+				}
+				if err != nil {
+					logErrorf("%s: %v\n", t.Hash, err)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, t := range plan {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d moves failed", failed, len(plan))
+	}
+	logf("%s: moved %d torrents to \"%s\"\n", rule.Name, len(plan), rule.To)
+	return nil
+}
+
+func moverListQbit(ctx context.Context, rule MoverRule) ([]moverTorrent, error) {
+	client := qbitCreateClient()
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{
+		Filter:   qbittorrent.TorrentFilterCompleted,
+		Category: rule.Category,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []moverTorrent
+	for _, t := range torrents {
+		result = append(result, moverTorrent{
+			Hash:        t.Hash,
+			Name:        t.Name,
+			Category:    t.Category,
+			SavePath:    hostPath(t.SavePath),
+			Size:        t.Size,
+			CompletedOn: t.CompletionOn,
+		})
+	}
+	return result, nil
+}
+
+func moverListDeluge(ctx context.Context, rule MoverRule) ([]moverTorrent, error) {
+	client := delugeCreateV2Client()
+	err := client.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	torrentsStatus, err := client.TorrentsStatus(ctx, deluge.StateUnspecified, nil)
+	if err != nil {
+		return nil, err
+	}
+	var labels map[string]string
+	if rule.Category != "" {
+		labels, err = delugeGetLabels(ctx, client, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []moverTorrent
+	for hash, ts := range torrentsStatus {
+		if !ts.IsFinished {
+			continue
+		}
+		result = append(result, moverTorrent{
+			Hash:        hash,
+			Name:        ts.Name,
+			Category:    labels[hash],
+			SavePath:    hostPath(ts.SavePath),
+			Size:        ts.TotalSize,
+			CompletedOn: ts.CompletedTime,
+		})
+	}
+	return result, nil
+}
+
+// isSubPath returns true if path is dir or is inside dir
+func isSubPath(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoverPlan(t *testing.T) {
+	rule := MoverRule{
+		Name:            "tv",
+		Client:          "qbit",
+		Category:        "tv",
+		From:            "/ssd/tv",
+		To:              "/hdd/tv",
+		MinCompletedAge: "2d",
+		MinFreeSpace:    "10GiB",
+	}
+	assert.NoError(t, rule.validate())
+
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) int64 { return now.Add(-time.Duration(n) * 24 * time.Hour).Unix() }
+	torrents := []moverTorrent{
+		{Hash: "new", Category: "tv", SavePath: "/ssd/tv", Size: 1 << 30, CompletedOn: daysAgo(1)},
+		{Hash: "old", Category: "tv", SavePath: "/ssd/tv", Size: 5 << 30, CompletedOn: daysAgo(9)},
+		{Hash: "older", Category: "tv", SavePath: "/ssd/tv/sub", Size: 4 << 30, CompletedOn: daysAgo(10)},
+		{Hash: "big", Category: "tv", SavePath: "/ssd/tv", Size: 8 << 30, CompletedOn: daysAgo(5)},
+		{Hash: "movie", Category: "movies", SavePath: "/ssd/tv", Size: 1 << 30, CompletedOn: daysAgo(9)},
+		{Hash: "elsewhere", Category: "tv", SavePath: "/ssd/tvx", Size: 1 << 30, CompletedOn: daysAgo(9)},
+		{Hash: "moved", Category: "tv", SavePath: "/hdd/tv/", Size: 1 << 30, CompletedOn: daysAgo(9)},
+		{Hash: "downloading", Category: "tv", SavePath: "/ssd/tv", Size: 1 << 30},
+	}
+
+	var checked []string
+	freeSpace := func(dest string) (int64, error) {
+		checked = append(checked, dest)
+		return 20 << 30, nil
+	}
+	plan := moverPlan(rule, torrents, freeSpace, now)
+	var hashes, dests []string
+	for _, t := range plan {
+		hashes = append(hashes, t.Hash)
+		dests = append(dests, t.Dest)
+	}
+	assert.Equal(t, []string{"older", "old"}, hashes)
+	assert.Equal(t, []string{filepath.FromSlash("/hdd/tv/sub"), filepath.FromSlash("/hdd/tv")}, dests)
+	assert.Contains(t, checked, filepath.FromSlash("/hdd/tv/sub"))
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// pathMapping rewrites paths between what the torrent client sees (e.g. inside its
+// Docker container) and what tt sees on the host.  Configure it in tt.toml as
+//
+//	[pathmap]
+//	prefixes = ["/downloads=/mnt/pool/downloads"]
+type pathMapping struct {
+	Client string
+	Host   string
+}
+
+// pathMapCache holds the parsed mappings, so that bad entries are reported once
+// rather than once per path
+var pathMapCache struct {
+	sync.Mutex
+	entries  []string
+	byClient []pathMapping // longest client prefix first
+	byHost   []pathMapping // longest host prefix first
+}
+
+// pathMappings returns the configured mappings sorted by client and by host prefix,
+// parsing them again only if the config changed
+func pathMappings() (byClient []pathMapping, byHost []pathMapping) {
+	entries := viper.GetStringSlice("pathmap.prefixes")
+	c := &pathMapCache
+	c.Lock()
+	defer c.Unlock()
+	if c.entries != nil && slices.Equal(c.entries, entries) {
+		return c.byClient, c.byHost
+	}
+
+	var mappings []pathMapping
+	for _, entry := range entries {
+		m, err := parsePathMapping(entry)
+		if err != nil {
+			logErrorf("pathmap: %v\n", err)
+			continue
+		}
+		mappings = append(mappings, m)
+	}
+	c.entries = slices.Clone(entries)
+	if c.entries == nil {
+		c.entries = []string{}
+	}
+	c.byClient = slices.Clone(mappings)
+	sort.SliceStable(c.byClient, func(i, j int) bool { return len(c.byClient[i].Client) > len(c.byClient[j].Client) })
+	c.byHost = slices.Clone(mappings)
+	sort.SliceStable(c.byHost, func(i, j int) bool { return len(c.byHost[i].Host) > len(c.byHost[j].Host) })
+	return c.byClient, c.byHost
+}
+
+// parsePathMapping parses "CLIENT_PREFIX=HOST_PREFIX"
+func parsePathMapping(entry string) (pathMapping, error) {
+	client, host, ok := strings.Cut(entry, "=")
+	client = strings.TrimSpace(client)
+	host = strings.TrimSpace(host)
+	if !ok || client == "" || host == "" {
+		return pathMapping{}, fmt.Errorf("invalid prefix mapping \"%s\", expected \"CLIENT_PREFIX=HOST_PREFIX\"", entry)
+	}
+	return pathMapping{Client: client, Host: host}, nil
+}
+
+// hostPath converts a path reported by a client to the path on this host
+func hostPath(clientPath string) string {
+	if clientPath == "" {
+		return clientPath
+	}
+	mappings, _ := pathMappings()
+	for _, m := range mappings {
+		if rest, ok := cutPathPrefix(clientPath, m.Client); ok {
+			return m.Host + rest
+		}
+	}
+	return clientPath
+}
+
+// clientPath converts a path on this host to the path the client sees
+func clientPath(hostPath string) string {
+	if hostPath == "" {
+		return hostPath
+	}
+	_, mappings := pathMappings()
+	for _, m := range mappings {
+		if rest, ok := cutPathPrefix(hostPath, m.Host); ok {
+			return m.Client + rest
+		}
+	}
+	return hostPath
+}
+
+// cutPathPrefix is like strings.CutPrefix but only matches whole path components
+func cutPathPrefix(path string, prefix string) (string, bool) {
+	prefix = strings.TrimRight(prefix, `/\`)
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok {
+		return path, false
+	}
+	if rest != "" && rest[0] != '/' && rest[0] != '\\' {
+		return path, false
+	}
+	return rest, true
+}
+
+// checkMsysPathConversion returns an error if msys is going to mangle path arguments
+// before they can be mapped
+func checkMsysPathConversion(force bool) error {
+	// OMG, msys does path conversion, turning "/a" into "c:/Program Files/Git/a".
+	// Do not allow this.
+	if os.Getenv("MSYSTEM") != "" {
+		if os.Getenv("MSYS_NO_PATHCONV") != "1" {
+			logf("Warning: MSYSTEM=%s, msys path conversion is in effect\n", os.Getenv("MSYSTEM"))
+			if !force {
+				return fmt.Errorf("msys path conversion in effect, rerun with MSYS_NO_PATHCONV=1 or --force")
+			}
+		}
+	}
+	return nil
+}
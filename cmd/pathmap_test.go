@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathMap(t *testing.T) {
+	viper.Set("pathmap.prefixes", []string{"/downloads=/mnt/pool/downloads", "/downloads/tv=/mnt/tv", "bogus"})
+	defer viper.Set("pathmap.prefixes", nil)
+
+	assert.Equal(t, "/mnt/pool/downloads/movies/x.mkv", hostPath("/downloads/movies/x.mkv"))
+	assert.Equal(t, "/mnt/tv/show", hostPath("/downloads/tv/show"))
+	assert.Equal(t, "/mnt/pool/downloads", hostPath("/downloads"))
+	assert.Equal(t, "/downloadsx/y", hostPath("/downloadsx/y"))
+	assert.Equal(t, "/data/y", hostPath("/data/y"))
+	assert.Equal(t, "", hostPath(""))
+
+	assert.Equal(t, "/downloads/movies", clientPath("/mnt/pool/downloads/movies"))
+	assert.Equal(t, "/downloads/tv/show", clientPath("/mnt/tv/show"))
+	assert.Equal(t, "/elsewhere", clientPath("/elsewhere"))
+}
+
+func TestPathMap_ReportsBadEntryOnce(t *testing.T) {
+	s, stdout, stderr := newTestLogSession(t)
+	assert.NoError(t, s.start(LogOptions{Format: "text"}))
+	viper.Set("pathmap.prefixes", []string{"bogus-once"})
+	defer viper.Set("pathmap.prefixes", nil)
+
+	hostPath("/a")
+	hostPath("/b")
+	clientPath("/c")
+
+	assert.Equal(t, 1, strings.Count(stdout.String()+stderr.String(), "invalid prefix mapping"))
+}
@@ -4,17 +4,51 @@ Copyright © 2025 Kenneth H. Cox
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// PurgeFile describes a hard-linked file found by purge
+type PurgeFile struct {
+	Path        string `json:"path"`
+	Device      uint64 `json:"device"`
+	Inode       uint64 `json:"inode"`
+	Size        int64  `json:"size"`
+	Nlink       uint64 `json:"nlink"`
+	TorrentFile string `json:"torrent_file,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// PurgeReport is the manifest of a purge run, printed with --report json
+type PurgeReport struct {
+	TorrentPath string      `json:"torrent_path"`
+	DryRun      bool        `json:"dry_run"`
+	Files       []PurgeFile `json:"files"`
+	// NumFiles and TotalBytes cover the copies that were (or would be) removed; files
+	// that could not be removed are listed with an error but not counted
+	NumFiles   int   `json:"num_files"`
+	TotalBytes int64 `json:"total_bytes"`
+	// ReclaimableBytes counts only files whose every link is either a matched copy
+	// or the torrent file itself, i.e. the space freed once the torrent data is removed
+	ReclaimableBytes int64 `json:"reclaimable_bytes"`
+}
+
 func init() {
 	rootCmd.AddCommand(purgeCmd)
 
 	purgeCmd.Flags().BoolP("dry-run", "n", false, "Run without removing anything")
 	purgeCmd.Flags().StringSliceP("scan-path", "p", []string{}, "Paths to look for hard-linked copies of the files in TORRENT_PATH")
+	purgeCmd.Flags().String("report", "", "Print a report of matched files to stdout (\"json\")")
 	viper.BindPFlag("purge.dry-run", purgeCmd.Flags().Lookup("dry-run"))
 	viper.BindPFlag("purge.scan-path", purgeCmd.Flags().Lookup("scan-path"))
+	viper.BindPFlag("purge.report", purgeCmd.Flags().Lookup("report"))
 }
 
 var purgeCmd = &cobra.Command{
@@ -32,20 +66,334 @@ file, e.g.
 Then run an automation at the time a torrent is removed, e.g.
 
   tt purge TORRENT_PATH
+
+To review what would be removed, or to keep an audit trail, print a JSON manifest
+of every matched file with its inode, size, link count and paired torrent file:
+
+  tt purge --dry-run --report json TORRENT_PATH > before.json
 `,
 	Args: cobra.MinimumNArgs(1),
 	Run:  purgeCmdRun,
 }
 
 func purgeCmdRun(cmd *cobra.Command, args []string) {
-	// get args
-	torrentPath := args[0]
+	// get args; the path comes from the client's hook, so map it to this host
+	torrentPath := hostPath(args[0])
+
+	// check flags
+	reportFormat := viper.GetString("purge.report")
+	if reportFormat != "" && reportFormat != "json" {
+		fatalError(fmt.Errorf("unknown report format: %s (expected json)", reportFormat))
+	}
+
+	// keep stdout clean for the report
+	if reportFormat != "" && viper.GetString("log-file") == "" && !viper.GetBool("quiet") {
+		logs.setOutput(os.Stderr, os.Stderr)
+	}
 
 	// get the flags and go
 	dryRun := viper.GetBool("purge.dry-run")
 	scanPaths := viper.GetStringSlice("purge.scan-path")
-	err := purgeCopies(torrentPath, scanPaths, dryRun)
+	report, err := purgeCopies(torrentPath, scanPaths, dryRun)
+	if report != nil && reportFormat == "json" {
+		if jsonErr := printPurgeReport(report); jsonErr != nil {
+			logErrorf("Error converting to JSON: %v\n", jsonErr)
+		}
+	}
 	if err != nil {
 		fatalError(err)
 	}
 }
+
+// purgeStat is the subset of file metadata purge needs.  A file is identified
+// by (Dev, Ino): device and inode on unix, volume serial and file index on Windows.
+type purgeStat struct {
+	Dev       uint64
+	Ino       uint64
+	Size      int64
+	Nlink     uint64
+	IsRegular bool
+	IsDir     bool
+}
+
+// purgeFS is the per-OS stat layer used by purge
+type purgeFS interface {
+	Lstat(path string) (purgeStat, error)
+	Unlink(path string) error
+}
+
+func purgeCopies(torrentPath string, scanPaths []string, dryRun bool) (*PurgeReport, error) {
+	return purgeCopiesFS(osPurgeFS, torrentPath, scanPaths, dryRun)
+}
+
+func purgeCopiesFS(pfs purgeFS, torrentPath string, scanPaths []string, dryRun bool) (*PurgeReport, error) {
+	targets, err := findPurgeTargets(pfs, torrentPath)
+	if err != nil {
+		return nil, err
+	}
+	return targets.purge(pfs, scanPaths, dryRun)
+}
+
+// purgeTargets are the hard-linked files in a torrent.  rm finds them before removing
+// the torrent, so that the copies can still be found after the torrent files are gone.
+type purgeTargets struct {
+	torrentPath string
+	device      uint64
+	files       map[uint64]PurgeFile
+}
+
+// findPurgeTargets remembers the device and inodes of all regular files in torrentPath
+// that have more than one link
+func findPurgeTargets(pfs purgeFS, torrentPath string) (*purgeTargets, error) {
+	// check that torrentPath exists and is a regular file or directory
+	stat, err := pfs.Lstat(torrentPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", torrentPath, err)
+	}
+
+	targets := &purgeTargets{torrentPath: torrentPath, device: stat.Dev, files: map[uint64]PurgeFile{}}
+	if stat.IsRegular {
+		vvLogf("%s: regular file (nlink: %d)\n", torrentPath, stat.Nlink)
+		if stat.Nlink > 1 {
+			targets.files[stat.Ino] = newPurgeFile(torrentPath, stat)
+		}
+	} else if stat.IsDir {
+		vvLogf("%s: directory\n", torrentPath)
+		targets.files = findAllFilesWithHardLinks(pfs, torrentPath)
+	} else {
+		return nil, fmt.Errorf("%s: not a regular file or directory", torrentPath)
+	}
+	vLogf("%s: found %d files with hard links\n", torrentPath, len(targets.files))
+	return targets, nil
+}
+
+// purge scans scanPaths for files linked to the targets and removes them
+func (pt *purgeTargets) purge(pfs purgeFS, scanPaths []string, dryRun bool) (*PurgeReport, error) {
+	// check that there is at least one scan path
+	if len(scanPaths) == 0 {
+		return nil, fmt.Errorf("no --scan-path specified")
+	}
+
+	base := filepath.Base(pt.torrentPath)
+	report := newPurgeReport(pt.torrentPath, dryRun)
+	defer report.finish()
+
+	// exit early if there are no inodes to look for
+	if len(pt.files) == 0 {
+		return report, nil
+	}
+
+	// scan paths for matching files and remove them
+	var lastError error
+	for _, scanPath := range scanPaths {
+		vLogf("scanning %s\n", scanPath)
+		stat, err := pfs.Lstat(scanPath)
+		if err != nil {
+			return report, fmt.Errorf("%s: %v", scanPath, err)
+		}
+		if stat.Dev != pt.device {
+			return report, fmt.Errorf("%s: different file system", scanPath)
+		}
+		dups := findMatchingFiles(pfs, scanPath, pt.files)
+		for _, dup := range dups {
+			if dryRun || verbosity > 0 {
+				logf("unlink %s\n", dup.Path)
+			}
+			if !dryRun {
+				err = pfs.Unlink(dup.Path)
+				if err != nil {
+					logErrorf("%s: error unlinking: %v\n", dup.Path, err)
+					dup.Error = err.Error()
+					lastError = err
+				}
+			}
+			report.add(dup)
+		}
+		if dryRun {
+			vLogf("%s: found %d linked copies in %s\n", base, len(dups), scanPath)
+		} else if len(dups) > 0 {
+			logf("%s: removed %d linked copies in %s\n", base, len(dups), scanPath)
+		}
+	}
+
+	return report, lastError
+}
+
+// countHardLinkedFiles returns the number of regular files in path that have more than one link
+func countHardLinkedFiles(pfs purgeFS, path string) (int, error) {
+	stat, err := pfs.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	if stat.IsDir {
+		return len(findAllFilesWithHardLinks(pfs, path)), nil
+	}
+	if stat.IsRegular && stat.Nlink > 1 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func newPurgeFile(path string, stat purgeStat) PurgeFile {
+	return PurgeFile{
+		Path:   path,
+		Device: stat.Dev,
+		Inode:  stat.Ino,
+		Size:   stat.Size,
+		Nlink:  stat.Nlink,
+	}
+}
+
+func findAllFilesWithHardLinks(pfs purgeFS, rootPath string) map[uint64]PurgeFile {
+	files := map[uint64]PurgeFile{}
+
+	// walk the directory tree returning inodes for all regular files with more than one link
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		vvLogf("visit %s\n", path)
+		base := filepath.Base(path)
+
+		// stop walking directories that can't be accessed
+		if err != nil && d.IsDir() {
+			vLogf("skipdir %s: %v\n", base, err)
+			return filepath.SkipDir
+		}
+
+		// ignore files that can't be accessed
+		if err != nil {
+			vLogf("%v\n", err)
+			return nil
+		}
+
+		// ignore directories
+		if d.IsDir() {
+			return nil
+		}
+
+		// stat the file
+		stat, err := pfs.Lstat(path)
+		if err != nil {
+			vLogf("%v\n", err)
+			return nil
+		}
+
+		// keep a regular file with more than one link
+		if stat.IsRegular && stat.Nlink > 1 {
+			vvLogf("match %s: ino=%d nlink=%d\n", base, stat.Ino, stat.Nlink)
+			files[stat.Ino] = newPurgeFile(path, stat)
+		}
+
+		return nil
+	})
+	if err != nil {
+		vLogf("%s: error walking directory: %v\n", rootPath, err)
+	}
+
+	return files
+}
+
+func findMatchingFiles(pfs purgeFS, rootPath string, torrentFiles map[uint64]PurgeFile) []PurgeFile {
+	var matches []PurgeFile
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		vvLogf("visit %s\n", path)
+
+		// stop walking directories that can't be accessed
+		if err != nil && d.IsDir() {
+			vLogf("skipdir %s: %v\n", path, err)
+			return filepath.SkipDir
+		}
+
+		// ignore files that can't be accessed
+		if err != nil {
+			return nil
+		}
+
+		// ignore directories
+		if d.IsDir() {
+			return nil
+		}
+
+		// stat the file
+		stat, err := pfs.Lstat(path)
+		if err != nil {
+			vLogf("%v\n", err)
+			return nil
+		}
+
+		// keep a regular file with a matching inode
+		if !stat.IsRegular {
+			return nil
+		}
+		if tf, ok := torrentFiles[stat.Ino]; ok {
+			vvLogf("%s: match ino=%d\n", path, stat.Ino)
+			match := newPurgeFile(path, stat)
+			match.TorrentFile = tf.Path
+			matches = append(matches, match)
+		}
+
+		return nil
+	})
+	if err != nil {
+		vLogf("%s: error walking directory: %v\n", rootPath, err)
+	}
+
+	return matches
+}
+
+func newPurgeReport(torrentPath string, dryRun bool) *PurgeReport {
+	return &PurgeReport{
+		TorrentPath: torrentPath,
+		DryRun:      dryRun,
+		Files:       []PurgeFile{},
+	}
+}
+
+// add records a matched file
+func (r *PurgeReport) add(f PurgeFile) {
+	r.Files = append(r.Files, f)
+}
+
+// purgeFileID identifies a file; copies with the same ID share their data
+type purgeFileID struct {
+	dev uint64
+	ino uint64
+}
+
+// finish computes the totals, counting the data shared by several copies once
+func (r *PurgeReport) finish() {
+	r.NumFiles = 0
+	r.TotalBytes = 0
+	r.ReclaimableBytes = 0
+
+	// count the copies of each file so we know which ones would be freed entirely
+	copies := map[purgeFileID]uint64{}
+	sizes := map[purgeFileID]int64{}
+	nlinks := map[purgeFileID]uint64{}
+	for _, f := range r.Files {
+		if f.Error != "" {
+			continue
+		}
+		r.NumFiles++
+		id := purgeFileID{f.Device, f.Inode}
+		copies[id]++
+		sizes[id] = f.Size
+		nlinks[id] = f.Nlink
+	}
+	for id, n := range copies {
+		r.TotalBytes += sizes[id]
+		// the +1 is the link in TORRENT_PATH
+		if n+1 >= nlinks[id] {
+			r.ReclaimableBytes += sizes[id]
+		}
+	}
+}
+
+func printPurgeReport(r *PurgeReport) error {
+	sort.SliceStable(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+	jsonOutput, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonOutput))
+	vLogf("%d files, %s total, %s reclaimable\n", r.NumFiles, humanizeBytes(r.TotalBytes), humanizeBytes(r.ReclaimableBytes))
+	return nil
+}
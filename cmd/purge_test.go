@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePurgeFS assigns file IDs by path, so hard links can be simulated
+// without relying on the semantics of the host file system
+type fakePurgeFS struct {
+	dev      uint64
+	stats    map[string]purgeStat
+	unlinked []string
+	failures map[string]error // Unlink returns these instead of unlinking
+}
+
+func newFakePurgeFS() *fakePurgeFS {
+	return &fakePurgeFS{dev: 1, stats: map[string]purgeStat{}}
+}
+
+func (f *fakePurgeFS) Lstat(path string) (purgeStat, error) {
+	if stat, ok := f.stats[path]; ok {
+		return stat, nil
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return purgeStat{}, err
+	}
+	return purgeStat{Dev: f.dev, IsDir: fi.IsDir(), IsRegular: fi.Mode().IsRegular(), Nlink: 1}, nil
+}
+
+func (f *fakePurgeFS) Unlink(path string) error {
+	if err := f.failures[path]; err != nil {
+		return err
+	}
+	f.unlinked = append(f.unlinked, path)
+	return nil
+}
+
+// link creates a file at each path and gives them all the same file ID
+func (f *fakePurgeFS) link(t *testing.T, id uint64, size int64, paths ...string) {
+	for _, path := range paths {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("%d", id)), 0644))
+		f.stats[path] = purgeStat{Dev: f.dev, Ino: id, Size: size, Nlink: uint64(len(paths)), IsRegular: true}
+	}
+}
+
+func TestPurgeCopies_MatchesLinkedFiles(t *testing.T) {
+	root := t.TempDir()
+	torrentPath := filepath.Join(root, "torrents", "Show.S01")
+	libraryPath := filepath.Join(root, "library")
+	pfs := newFakePurgeFS()
+	pfs.link(t, 10, 1000, filepath.Join(torrentPath, "e01.mkv"), filepath.Join(libraryPath, "Show", "e01.mkv"))
+	pfs.link(t, 11, 2000, filepath.Join(torrentPath, "e02.mkv"), filepath.Join(libraryPath, "Show", "e02.mkv"), filepath.Join(root, "elsewhere.mkv"))
+	pfs.link(t, 12, 3000, filepath.Join(torrentPath, "e03.nfo"))
+	pfs.link(t, 13, 4000, filepath.Join(libraryPath, "Other", "x.mkv"), filepath.Join(root, "x.mkv"))
+
+	report, err := purgeCopiesFS(pfs, torrentPath, []string{libraryPath}, true)
+	assert.NoError(t, err)
+	assert.Empty(t, pfs.unlinked)
+	assert.Equal(t, 2, report.NumFiles)
+	assert.Equal(t, int64(3000), report.TotalBytes)
+	assert.Equal(t, int64(1000), report.ReclaimableBytes)
+	assert.Equal(t, filepath.Join(torrentPath, "e01.mkv"), report.Files[0].TorrentFile)
+	assert.Equal(t, uint64(3), report.Files[1].Nlink)
+}
+
+func TestPurgeCopies_Unlinks(t *testing.T) {
+	root := t.TempDir()
+	torrentPath := filepath.Join(root, "torrents", "movie.mkv")
+	libraryPath := filepath.Join(root, "library")
+	pfs := newFakePurgeFS()
+	pfs.link(t, 20, 500, torrentPath, filepath.Join(libraryPath, "movie.mkv"))
+
+	report, err := purgeCopiesFS(pfs, torrentPath, []string{libraryPath}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(libraryPath, "movie.mkv")}, pfs.unlinked)
+	assert.Equal(t, int64(500), report.ReclaimableBytes)
+}
+
+func TestPurgeCopies_DifferentFileSystem(t *testing.T) {
+	root := t.TempDir()
+	torrentPath := filepath.Join(root, "torrents", "movie.mkv")
+	libraryPath := filepath.Join(root, "library")
+	pfs := newFakePurgeFS()
+	pfs.link(t, 20, 500, torrentPath)
+	pfs.stats[torrentPath] = purgeStat{Dev: 2, Ino: 20, Nlink: 2, IsRegular: true}
+	assert.NoError(t, os.MkdirAll(libraryPath, 0755))
+
+	_, err := purgeCopiesFS(pfs, torrentPath, []string{libraryPath}, true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "different file system")
+}
+
+func TestPurgeCopies_Totals(t *testing.T) {
+	root := t.TempDir()
+	torrentPath := filepath.Join(root, "torrents", "Show.S01")
+	libraryPath := filepath.Join(root, "library")
+	pfs := newFakePurgeFS()
+	pfs.link(t, 30, 1000, filepath.Join(torrentPath, "e01.mkv"), filepath.Join(libraryPath, "a", "e01.mkv"), filepath.Join(libraryPath, "b", "e01.mkv"))
+	pfs.link(t, 31, 2000, filepath.Join(torrentPath, "e02.mkv"), filepath.Join(libraryPath, "a", "e02.mkv"))
+	pfs.failures = map[string]error{filepath.Join(libraryPath, "a", "e02.mkv"): fmt.Errorf("permission denied")}
+
+	// two copies of e01 share their data, and e02 could not be removed
+	report, err := purgeCopiesFS(pfs, torrentPath, []string{libraryPath}, false)
+	assert.Error(t, err)
+	assert.Len(t, report.Files, 3)
+	assert.Equal(t, 2, report.NumFiles)
+	assert.Equal(t, int64(1000), report.TotalBytes)
+	assert.Equal(t, int64(1000), report.ReclaimableBytes)
+}
+
+func TestPurgeCopies_TotalsOnError(t *testing.T) {
+	root := t.TempDir()
+	torrentPath := filepath.Join(root, "torrents", "movie.mkv")
+	libraryPath := filepath.Join(root, "library")
+	pfs := newFakePurgeFS()
+	pfs.link(t, 40, 500, torrentPath, filepath.Join(libraryPath, "movie.mkv"))
+
+	// the second scan path fails after the first one matched
+	report, err := purgeCopiesFS(pfs, torrentPath, []string{libraryPath, filepath.Join(root, "missing")}, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1, report.NumFiles)
+	assert.Equal(t, int64(500), report.TotalBytes)
+}
@@ -3,9 +3,53 @@
 package cmd
 
 import (
-	"fmt"
+	"golang.org/x/sys/windows"
 )
 
-func purgeCopies(_ string, _ []string, _ bool) error {
-	return fmt.Errorf("purge not supported on Windows")
+// windowsPurgeFS identifies files by volume serial number and file index,
+// the NTFS equivalent of device and inode
+type windowsPurgeFS struct{}
+
+var osPurgeFS purgeFS = windowsPurgeFS{}
+
+func (windowsPurgeFS) Lstat(path string) (purgeStat, error) {
+	pathp, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return purgeStat{}, err
+	}
+
+	// open without following reparse points; FILE_FLAG_BACKUP_SEMANTICS is needed to open directories
+	h, err := windows.CreateFile(pathp, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS|windows.FILE_FLAG_OPEN_REPARSE_POINT, 0)
+	if err != nil {
+		return purgeStat{}, err
+	}
+	defer windows.CloseHandle(h)
+
+	var info windows.ByHandleFileInformation
+	err = windows.GetFileInformationByHandle(h, &info)
+	if err != nil {
+		return purgeStat{}, err
+	}
+
+	isDir := info.FileAttributes&windows.FILE_ATTRIBUTE_DIRECTORY != 0
+	isReparse := info.FileAttributes&windows.FILE_ATTRIBUTE_REPARSE_POINT != 0
+	return purgeStat{
+		Dev:       uint64(info.VolumeSerialNumber),
+		Ino:       uint64(info.FileIndexHigh)<<32 | uint64(info.FileIndexLow),
+		Size:      int64(info.FileSizeHigh)<<32 | int64(info.FileSizeLow),
+		Nlink:     uint64(info.NumberOfLinks),
+		IsRegular: !isDir && !isReparse,
+		IsDir:     isDir && !isReparse,
+	}, nil
+}
+
+func (windowsPurgeFS) Unlink(path string) error {
+	pathp, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	return windows.DeleteFile(pathp)
 }
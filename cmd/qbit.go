@@ -19,9 +19,11 @@ func init() {
 	qbitCmd.PersistentFlags().StringP("server", "s", "http://localhost:8080", "server url")
 	qbitCmd.PersistentFlags().StringP("username", "U", "admin", "server username")
 	qbitCmd.PersistentFlags().StringP("password", "P", "password", "server password")
+	qbitCmd.PersistentFlags().String("instance", "", "server instance from a [qbit.NAME] section of the config")
 	viper.BindPFlag("qbit.server", qbitCmd.PersistentFlags().Lookup("server"))
 	viper.BindPFlag("qbit.username", qbitCmd.PersistentFlags().Lookup("username"))
 	viper.BindPFlag("qbit.password", qbitCmd.PersistentFlags().Lookup("password"))
+	viper.BindPFlag("qbit.instance", qbitCmd.PersistentFlags().Lookup("instance"))
 }
 
 var qbitCmd = &cobra.Command{
@@ -33,19 +35,42 @@ var qbitCmd = &cobra.Command{
 	},
 }
 
+// qbitCreateClient creates a client for the instance selected by --instance
 func qbitCreateClient() *internal.QbitClient {
-	if viper.GetInt("verbose") > 0 {
-		stdoutLogger.Printf("Connecting to %s as user %s\n", viper.GetString("qbit.server"), viper.GetString("qbit.username"))
+	return qbitCreateInstanceClient(currentInstance("qbit"))
+}
+
+func qbitCreateInstanceClient(instance string) *internal.QbitClient {
+	client, err := qbitNewInstanceClient(instance)
+	if err != nil {
+		fatalError(err)
 	}
+	return client
+}
+
+// qbitNewInstanceClient is qbitCreateInstanceClient for callers that handle errors themselves
+func qbitNewInstanceClient(instance string) (*internal.QbitClient, error) {
+	server := viper.GetString(qbitInstanceKey(instance, "server"))
+	username := viper.GetString(qbitInstanceKey(instance, "username"))
+	password, err := instancePassword(qbitCmd.PersistentFlags(), "qbit", instance)
+	if err != nil {
+		return nil, err
+	}
+	vLogf("Connecting to %s as user %s\n", server, username)
 	return internal.NewQbitClient(qbittorrent.Config{
-		Host:     viper.GetString("qbit.server"),
-		Username: viper.GetString("qbit.username"),
-		Password: viper.GetString("qbit.password"),
-	})
+		Host:     server,
+		Username: username,
+		Password: password,
+	}), nil
+}
+
+// qbitInstanceKey returns the viper key holding a connection setting for instance
+func qbitInstanceKey(instance string, key string) string {
+	return instanceKey(qbitCmd.PersistentFlags(), "qbit", instance, key)
 }
 
-func qbitGetHostPort() (string, string, error) {
-	server := viper.GetString("qbit.server")
+func qbitGetHostPort(instance string) (string, string, error) {
+	server := viper.GetString(qbitInstanceKey(instance, "server"))
 	u, err := url.Parse(server)
 	if err != nil {
 		return "", "", err
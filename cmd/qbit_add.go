@@ -0,0 +1,176 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+func init() {
+	qbitCmd.AddCommand(qbitAddCmd)
+
+	qbitAddCmd.Flags().StringP("save-path", "s", "", "Save path (host path, see [pathmap])")
+	qbitAddCmd.Flags().StringP("category", "c", "", "Category")
+	qbitAddCmd.Flags().StringP("tags", "t", "", "Comma-separated tags")
+	qbitAddCmd.Flags().BoolP("paused", "p", false, "Add in the paused state")
+	qbitAddCmd.Flags().Bool("skip-check", false, "Skip the hash check")
+	qbitAddCmd.Flags().BoolP("reannounce", "r", false, "Reannounce each torrent until healthy, per the reannounce settings")
+	qbitAddCmd.Flags().StringP("watch", "w", "", "Watch DIR for .torrent and .magnet files")
+	qbitAddCmd.Flags().Int("interval", 10, "Seconds between scans of the watch dir")
+	qbitAddCmd.Flags().Bool("once", false, "Scan the watch dir once and exit")
+	viper.BindPFlag("qbit.add.save-path", qbitAddCmd.Flags().Lookup("save-path"))
+	viper.BindPFlag("qbit.add.category", qbitAddCmd.Flags().Lookup("category"))
+	viper.BindPFlag("qbit.add.tags", qbitAddCmd.Flags().Lookup("tags"))
+	viper.BindPFlag("qbit.add.paused", qbitAddCmd.Flags().Lookup("paused"))
+	viper.BindPFlag("qbit.add.skip-check", qbitAddCmd.Flags().Lookup("skip-check"))
+	viper.BindPFlag("qbit.add.reannounce", qbitAddCmd.Flags().Lookup("reannounce"))
+	viper.BindPFlag("qbit.add.watch", qbitAddCmd.Flags().Lookup("watch"))
+	viper.BindPFlag("qbit.add.interval", qbitAddCmd.Flags().Lookup("interval"))
+	viper.BindPFlag("qbit.add.once", qbitAddCmd.Flags().Lookup("once"))
+}
+
+var qbitAddCmd = &cobra.Command{
+	Use:   "add [FILE.torrent|FILE.magnet|MAGNET_URI]...",
+	Short: "Add torrents",
+	Long: `Add torrents from .torrent files, .magnet files or magnet URIs.
+
+With --watch DIR, add every .torrent and .magnet file that appears in DIR,
+renaming each to *.added or *.failed afterwards.  Files still being written are
+left alone until they stop changing between scans.`,
+	Run: qbitAddCmdRun,
+}
+
+func qbitAddCmdRun(cmd *cobra.Command, args []string) {
+	opts := AddOptions{
+		SavePath:   viper.GetString("qbit.add.save-path"),
+		Category:   viper.GetString("qbit.add.category"),
+		Tags:       viper.GetString("qbit.add.tags"),
+		Paused:     viper.GetBool("qbit.add.paused"),
+		SkipCheck:  viper.GetBool("qbit.add.skip-check"),
+		Reannounce: viper.GetBool("qbit.add.reannounce"),
+		Watch:      viper.GetString("qbit.add.watch"),
+		Interval:   viper.GetInt("qbit.add.interval"),
+		Once:       viper.GetBool("qbit.add.once"),
+	}
+
+	// create a qbit client
+	client := qbitCreateClient()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := qbitAdd(ctx, client, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func qbitAdd(ctx context.Context, client internal.QbitClientInterface, args []string, opts AddOptions) error {
+	if len(args) == 0 && opts.Watch == "" {
+		return fmt.Errorf("nothing to add; give a file, a magnet URI, or --watch DIR")
+	}
+
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// reannounces run in the background so adding is not held up
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	add := func(src addSource) error {
+		err := qbitAddSource(ctx, client, src, opts)
+		if err == nil && opts.Reannounce {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				qbitAddReannounce(ctx, client, src.Hash)
+			}()
+		}
+		return err
+	}
+
+	// add files and magnets
+	numFailed := 0
+	for _, arg := range args {
+		src, err := loadAddSource(arg)
+		if err == nil {
+			err = add(src)
+		}
+		if err != nil {
+			logErrorf("%s: %v\n", arg, err)
+			numFailed++
+		}
+	}
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d torrents could not be added", numFailed, len(args))
+	}
+
+	// watch
+	if opts.Watch != "" {
+		return watchDir(ctx, opts.Watch, time.Duration(opts.Interval)*time.Second, opts.Once, add)
+	}
+	return nil
+}
+
+func qbitAddSource(ctx context.Context, client internal.QbitClientInterface, src addSource, opts AddOptions) error {
+	addOptions := qbittorrent.TorrentAddOptions{
+		Paused:        opts.Paused,
+		SkipHashCheck: opts.SkipCheck,
+		Category:      opts.Category,
+		Tags:          opts.Tags,
+	}
+	if opts.SavePath != "" {
+		addOptions.SavePath = clientPath(opts.SavePath)
+	}
+
+	var err error
+	if src.Magnet != "" {
+		err = client.AddTorrentFromUrlCtx(ctx, src.Magnet, addOptions.Prepare())
+	} else {
+		err = client.AddTorrentFromMemoryCtx(ctx, src.Data, addOptions.Prepare())
+	}
+	if err != nil {
+		return err
+	}
+	logf("%s: added \"%s\"\n", src.Hash, src.name())
+	return nil
+}
+
+// qbitAddReannounce waits for a newly added torrent to show up, then reannounces it until healthy
+func qbitAddReannounce(ctx context.Context, client internal.QbitClientInterface, hash string) {
+	err := qbitWaitForTorrent(ctx, client, hash)
+	if err == nil {
+		err = qbitReannounce(ctx, client, hash, qbitReannounceOptions())
+	}
+	if err != nil {
+		logErrorf("%s: %v\n", hash, err)
+	}
+}
+
+// qbitWaitForTorrent waits for a newly added torrent to show up, since qbit adds torrents asynchronously
+func qbitWaitForTorrent(ctx context.Context, client internal.QbitClientInterface, hash string) error {
+	found, err := waitForAdded(ctx, func(ctx context.Context) (bool, error) {
+		torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{hash}})
+		return len(torrents) > 0, err
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s: torrent did not show up after adding", hash)
+	}
+	return nil
+}
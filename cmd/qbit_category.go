@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+func init() {
+	qbitCmd.AddCommand(qbitCategoryCmd)
+	qbitCategoryCmd.AddCommand(qbitCategoryListCmd)
+	qbitCategoryCmd.AddCommand(qbitCategorySetCmd)
+	qbitCategoryCmd.AddCommand(qbitCategoryCreateCmd)
+
+	qbitCategorySetCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	qbitCategorySetCmd.Flags().BoolP("dry-run", "n", false, "Print the changes without making them")
+	viper.BindPFlag("qbit.category.filter", qbitCategorySetCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("qbit.category.dry-run", qbitCategorySetCmd.Flags().Lookup("dry-run"))
+}
+
+var qbitCategoryCmd = &cobra.Command{
+	Use:     "category",
+	Aliases: []string{"cat"},
+	Short:   "Manage categories",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var qbitCategoryListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List categories",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := qbitCategoryList(context.Background(), qbitCreateClient())
+		if err != nil {
+			fatalError(err)
+		}
+	},
+}
+
+var qbitCategorySetCmd = &cobra.Command{
+	Use:   "set CATEGORY [hash]...",
+	Short: "Set the category of torrents selected by hash or --filter",
+	Long: `Set the category of torrents selected by hash or --filter.
+Use "" as the CATEGORY to remove the category.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := TagOptions{
+			Filter: viper.GetString("qbit.category.filter"),
+			DryRun: viper.GetBool("qbit.category.dry-run"),
+		}
+		err := qbitCategorySet(context.Background(), qbitCreateClient(), args[0], args[1:], opts)
+		if err != nil {
+			fatalError(err)
+		}
+	},
+}
+
+var qbitCategoryCreateCmd = &cobra.Command{
+	Use:   "create CATEGORY [save_path]",
+	Short: "Create a category",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		savePath := ""
+		if len(args) > 1 {
+			savePath = args[1]
+		}
+		err := qbitCategoryCreate(context.Background(), qbitCreateClient(), args[0], savePath)
+		if err != nil {
+			fatalError(err)
+		}
+	},
+}
+
+func qbitCategoryList(ctx context.Context, client internal.QbitClientInterface) error {
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	categories, err := client.GetCategoriesCtx(ctx)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// print as CSV
+	fmt.Printf("category,save_path\n")
+	for _, name := range names {
+		fmt.Printf("%s,%s\n", name, hostPath(categories[name].SavePath))
+	}
+	return nil
+}
+
+func qbitCategorySet(ctx context.Context, client internal.QbitClientInterface, category string, hashes []string, opts TagOptions) error {
+	if len(hashes) == 0 && opts.Filter == "" {
+		return fmt.Errorf("no torrents specified; give a hash or --filter")
+	}
+
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// check that the category exists, so a typo doesn't silently create one
+	if category != "" {
+		categories, err := client.GetCategoriesCtx(ctx)
+		if err != nil {
+			return err
+		}
+		if _, ok := categories[category]; !ok {
+			return fmt.Errorf("%s: category not found, create it first", category)
+		}
+	}
+
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, RmOptions{Filter: opts.Filter})
+	if err != nil {
+		return err
+	}
+	var selected []string
+	for _, t := range torrents {
+		if t.Category == category {
+			continue
+		}
+		selected = append(selected, t.Hash)
+		logf("%s: category \"%s\" -> \"%s\" \"%s\"\n", t.Hash, t.Category, category, t.Name)
+	}
+	if len(selected) == 0 || opts.DryRun {
+		return nil
+	}
+
+	return client.SetCategoryCtx(ctx, selected, category)
+}
+
+func qbitCategoryCreate(ctx context.Context, client internal.QbitClientInterface, category string, savePath string) error {
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.CreateCategoryCtx(ctx, category, clientPath(savePath))
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestQbitCategorySet_Filter(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	categories := map[string]qbittorrent.Category{"tv": {Name: "tv"}}
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "Show.S01E01", Category: ""},
+		{Hash: "b", Name: "Show.S01E02", Category: "tv"},
+		{Hash: "c", Name: "Movie", Category: ""},
+	}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetCategoriesCtx", ctx).Return(categories, nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("SetCategoryCtx", ctx, []string{"a"}, "tv").Return(nil)
+
+	err := qbitCategorySet(ctx, mockClient, "tv", nil, TagOptions{Filter: "show"})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestQbitCategorySet_UnknownCategory(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetCategoriesCtx", ctx).Return(map[string]qbittorrent.Category{}, nil)
+
+	err := qbitCategorySet(ctx, mockClient, "tvv", []string{"a"}, TagOptions{})
+	assert.Error(t, err)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+// ControlOptions selects the torrents for pause, resume, recheck and force-start
+type ControlOptions struct {
+	Filter string
+	All    bool
+}
+
+// qbitControlAction is a bulk command that applies one API call to the selected torrents
+type qbitControlAction struct {
+	Name    string
+	Aliases []string
+	Short   string
+	Apply   func(ctx context.Context, client internal.QbitClientInterface, hashes []string) error
+}
+
+var qbitControlActions = []qbitControlAction{
+	{
+		Name:    "pause",
+		Aliases: []string{"stop"},
+		Short:   "Pause torrents",
+		Apply: func(ctx context.Context, client internal.QbitClientInterface, hashes []string) error {
+			return client.PauseCtx(ctx, hashes)
+		},
+	},
+	{
+		Name:    "resume",
+		Aliases: []string{"unpause"},
+		Short:   "Resume torrents",
+		Apply: func(ctx context.Context, client internal.QbitClientInterface, hashes []string) error {
+			return client.ResumeCtx(ctx, hashes)
+		},
+	},
+	{
+		Name:    "recheck",
+		Aliases: []string{"check", "verify"},
+		Short:   "Recheck torrents",
+		Apply: func(ctx context.Context, client internal.QbitClientInterface, hashes []string) error {
+			return client.RecheckCtx(ctx, hashes)
+		},
+	},
+	{
+		Name:    "force-start",
+		Aliases: []string{"force"},
+		Short:   "Force start torrents, ignoring queue limits",
+		Apply: func(ctx context.Context, client internal.QbitClientInterface, hashes []string) error {
+			return client.SetForceStartCtx(ctx, hashes, true)
+		},
+	},
+}
+
+func init() {
+	for _, action := range qbitControlActions {
+		cmd := &cobra.Command{
+			Use:     action.Name + " [hash]...",
+			Aliases: action.Aliases,
+			Short:   action.Short,
+			Long:    action.Short + " selected by hash, by --filter, or --all.",
+			Run: func(cmd *cobra.Command, args []string) {
+				qbitControlCmdRun(action, args)
+			},
+		}
+		qbitCmd.AddCommand(cmd)
+
+		cmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+		cmd.Flags().Bool("all", false, "Select all torrents")
+		viper.BindPFlag("qbit."+action.Name+".filter", cmd.Flags().Lookup("filter"))
+		viper.BindPFlag("qbit."+action.Name+".all", cmd.Flags().Lookup("all"))
+	}
+}
+
+func qbitControlCmdRun(action qbitControlAction, args []string) {
+	opts := ControlOptions{
+		Filter: viper.GetString("qbit." + action.Name + ".filter"),
+		All:    viper.GetBool("qbit." + action.Name + ".all"),
+	}
+
+	// create a qbit client
+	client := qbitCreateClient()
+
+	err := qbitControl(context.Background(), client, action, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func qbitControl(ctx context.Context, client internal.QbitClientInterface, action qbitControlAction, hashes []string, opts ControlOptions) error {
+	if len(hashes) == 0 && opts.Filter == "" && !opts.All {
+		return fmt.Errorf("no torrents specified; give a hash, --filter, or --all")
+	}
+
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, RmOptions{Filter: opts.Filter})
+	if err != nil {
+		return err
+	}
+	if len(torrents) == 0 {
+		vLogf("No torrents to %s\n", action.Name)
+		return nil
+	}
+	var selected []string
+	for _, t := range torrents {
+		selected = append(selected, t.Hash)
+		vLogf("%s: %s \"%s\"\n", t.Hash, action.Name, t.Name)
+	}
+
+	err = action.Apply(ctx, client, selected)
+	if err != nil {
+		return err
+	}
+	logf("%s: %d torrents\n", action.Name, len(selected))
+	return nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func qbitFindControlAction(name string) qbitControlAction {
+	for _, action := range qbitControlActions {
+		if action.Name == name {
+			return action
+		}
+	}
+	panic("no action " + name)
+}
+
+func TestQbitControl_PauseFilter(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "Show.S01E01"},
+		{Hash: "b", Name: "Movie"},
+	}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("PauseCtx", ctx, []string{"a"}).Return(nil)
+
+	err := qbitControl(ctx, mockClient, qbitFindControlAction("pause"), nil, ControlOptions{Filter: "show"})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestQbitControl_ForceStart(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	torrents := []qbittorrent.Torrent{{Hash: "a", Name: "A"}}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("SetForceStartCtx", ctx, []string{"a"}, true).Return(nil)
+
+	err := qbitControl(ctx, mockClient, qbitFindControlAction("force-start"), []string{"a"}, ControlOptions{})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestQbitControl_RequiresSelection(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+
+	err := qbitControl(context.Background(), mockClient, qbitFindControlAction("recheck"), nil, ControlOptions{})
+	assert.Error(t, err)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+func init() {
+	qbitCmd.AddCommand(qbitExportCmd)
+
+	qbitExportCmd.Flags().StringP("dir", "d", "", "Directory to export to")
+	qbitExportCmd.Flags().Bool("by-name", false, "Name files \"NAME [HASH].torrent\" instead of \"HASH.torrent\"")
+	qbitExportCmd.Flags().Bool("by-category", false, "Export into a subdirectory per category")
+	qbitExportCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	qbitExportCmd.Flags().BoolP("dry-run", "n", false, "Print what would be exported")
+	viper.BindPFlag("qbit.export.dir", qbitExportCmd.Flags().Lookup("dir"))
+	viper.BindPFlag("qbit.export.by-name", qbitExportCmd.Flags().Lookup("by-name"))
+	viper.BindPFlag("qbit.export.by-category", qbitExportCmd.Flags().Lookup("by-category"))
+	viper.BindPFlag("qbit.export.filter", qbitExportCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("qbit.export.dry-run", qbitExportCmd.Flags().Lookup("dry-run"))
+}
+
+var qbitExportCmd = &cobra.Command{
+	Use:   "export [hash]...",
+	Short: "Export .torrent files for backup",
+	Long: `Export the .torrent file of each torrent to --dir, skipping torrents
+already exported there.  Safe to run from cron.`,
+	Run: qbitExportCmdRun,
+}
+
+func qbitExportCmdRun(cmd *cobra.Command, args []string) {
+	opts := ExportOptions{
+		Dir:        viper.GetString("qbit.export.dir"),
+		ByName:     viper.GetBool("qbit.export.by-name"),
+		ByCategory: viper.GetBool("qbit.export.by-category"),
+		Filter:     viper.GetString("qbit.export.filter"),
+		DryRun:     viper.GetBool("qbit.export.dry-run"),
+	}
+
+	// create a qbit client
+	client := qbitCreateClient()
+
+	err := qbitExport(context.Background(), client, args, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+func qbitExport(ctx context.Context, client internal.QbitClientInterface, hashes []string, opts ExportOptions) error {
+	if opts.Dir == "" {
+		return fmt.Errorf("no export directory; give --dir")
+	}
+
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, RmOptions{})
+	if err != nil {
+		return err
+	}
+	var items []exportItem
+	for _, t := range torrents {
+		items = append(items, exportItem{Hash: t.Hash, Name: t.Name, Category: t.Category})
+	}
+
+	return exportTorrents(items, opts, func(hash string) ([]byte, error) {
+		return client.ExportTorrentCtx(ctx, hash)
+	})
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestQbitExport_SkipsExported(t *testing.T) {
+	hashA := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	hashB := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "old"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "old", hashA+".torrent"), testTorrent, 0644))
+
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	torrents := []qbittorrent.Torrent{
+		{Hash: hashA, Name: "A"},
+		{Hash: hashB, Name: "Show: S01", Category: "tv/hd"},
+	}
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("ExportTorrentCtx", ctx, hashB).Return(testTorrent, nil)
+
+	err := qbitExport(ctx, mockClient, nil, ExportOptions{Dir: dir, ByName: true, ByCategory: true})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "tv", "hd", "Show_ S01 ["+hashB+"].torrent"))
+	assert.NoError(t, err)
+	assert.Equal(t, testTorrent, data)
+	mockClient.AssertExpectations(t)
+}
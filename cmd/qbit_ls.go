@@ -36,18 +36,21 @@ func init() {
 var qbitValidColumns = []string{
 	"added",
 	"audio",
+	"category",
 	"channels",
 	"completed",
 	"download_path",
 	"downloaded",
 	"group",
 	"hash",
+	"instance",
 	"name",
 	// "next_announce", // for this we need to call GetTorrentPropertiesCtx
 	"ratio",
 	// "reannounce", // for this we need to call GetTorrentPropertiesCtx
 	"save_path",
 	"seed_time",
+	"size",
 	"state",
 	"tags",
 	"uploaded",
@@ -66,14 +69,14 @@ func qbitListCmdRun(cmd *cobra.Command, args []string) {
 
 	// check flags
 	columns := viper.GetStringSlice("qbit.columns")
-	for _, column := range columns {
-		if !slices.Contains(qbitValidColumns, column) {
-			fatalError(fmt.Errorf("unknown column: %s (expected one of {%s})", column, strings.Join(qbitValidColumns, ", ")))
-		}
+	if err := checkColumns(columns, qbitValidColumns); err != nil {
+		fatalError(err)
 	}
 
-	// create a qbit client
-	client := qbitCreateClient()
+	instances, err := selectInstances("qbit", viper.GetString("qbit.instance"))
+	if err != nil {
+		fatalError(err)
+	}
 
 	// collect options and go
 	opts := ListOptions{
@@ -83,7 +86,12 @@ func qbitListCmdRun(cmd *cobra.Command, args []string) {
 		NoHeader: viper.GetBool("qbit.noheader"),
 		Humanize: viper.GetBool("qbit.humanize"),
 	}
-	err := qbitList(context.Background(), client, hashes, opts)
+	err = forEachInstance(instances, func(i int, instance string) error {
+		instanceOpts := opts
+		instanceOpts.Instance = instance
+		instanceOpts.NoHeader = opts.NoHeader || i > 0
+		return qbitList(context.Background(), qbitCreateInstanceClient(instance), hashes, instanceOpts)
+	})
 	if err != nil {
 		fatalError(err)
 	}
@@ -116,13 +124,18 @@ func qbitList(ctx context.Context, client internal.QbitClientInterface, hashes [
 	}
 	vLogf("Found %d torrents\n", len(torrents))
 
-	// print as CSV
+	qbitPrintTorrents(torrents, opts)
+	return nil
+}
+
+// qbitPrintTorrents prints the torrents that match opts.Filter as CSV
+func qbitPrintTorrents(torrents []qbittorrent.Torrent, opts ListOptions) {
 	if !opts.NoHeader {
 		fmt.Printf("%s\n", strings.Join(opts.Columns, ","))
 	}
 	for _, t := range torrents {
 		// skip if the name doesn't match the filter
-		if opts.Filter != "" && !strings.Contains(strings.ToLower(t.Name), strings.ToLower(opts.Filter)) {
+		if !matchesFilter(t.Name, opts.Filter) {
 			continue
 		}
 
@@ -130,12 +143,14 @@ func qbitList(ctx context.Context, client internal.QbitClientInterface, hashes [
 		var line []string
 		r := rls.ParseString(t.Name)
 		for _, column := range opts.Columns {
+			if column == "instance" {
+				line = append(line, opts.Instance)
+				continue
+			}
 			line = append(line, qbitFormatColumn(column, t, r, opts.Humanize))
 		}
 		fmt.Printf("%s\n", strings.Join(line, ","))
 	}
-
-	return nil
 }
 
 // format the given column
@@ -145,12 +160,14 @@ func qbitFormatColumn(column string, t qbittorrent.Torrent, r rls.Release, human
 		return formatTimestamp(int64(t.AddedOn))
 	case "audio":
 		return strings.Join(r.Audio, " ")
+	case "category":
+		return t.Category
 	case "channels":
 		return r.Channels
 	case "completed":
 		return formatTimestamp(t.CompletionOn)
 	case "download_path":
-		return t.DownloadPath
+		return hostPath(t.DownloadPath)
 	case "downloaded":
 		if humanize {
 			return humanizeBytes(t.Downloaded)
@@ -165,9 +182,14 @@ func qbitFormatColumn(column string, t qbittorrent.Torrent, r rls.Release, human
 	case "ratio":
 		return fmt.Sprintf("%.1f", t.Ratio)
 	case "save_path":
-		return t.SavePath
+		return hostPath(t.SavePath)
 	case "seed_time":
 		return (time.Duration(t.SeedingTime) * time.Second).String()
+	case "size":
+		if humanize {
+			return humanizeBytes(t.Size)
+		}
+		return fmt.Sprintf("%d", t.Size)
 	case "state":
 		return string(t.State)
 	case "tags":
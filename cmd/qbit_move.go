@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+func init() {
+	qbitCmd.AddCommand(qbitMoveCmd)
+
+	qbitMoveCmd.Flags().BoolP("force", "f", false, "Force move")
+	qbitMoveCmd.Flags().BoolP("wait", "w", false, "Wait for the move to finish and verify the files")
+	qbitMoveCmd.Flags().IntP("timeout", "t", 60*60, "Maximum time to wait in seconds")
+	viper.BindPFlag("qbit.move.force", qbitMoveCmd.Flags().Lookup("force"))
+	viper.BindPFlag("qbit.move.wait", qbitMoveCmd.Flags().Lookup("wait"))
+	viper.BindPFlag("qbit.move.timeout", qbitMoveCmd.Flags().Lookup("timeout"))
+}
+
+var qbitMoveCmd = &cobra.Command{
+	Use:     "move hash path",
+	Aliases: []string{"mv", "m"},
+	Short:   "Move torrent",
+	Args:    cobra.ExactArgs(2),
+	Run:     qbitMoveCmdRun,
+}
+
+func qbitMoveCmdRun(cmd *cobra.Command, args []string) {
+	hash := args[0]
+	path := args[1]
+
+	// get the flags
+	force := viper.GetBool("qbit.move.force")
+	opts := MoveOptions{
+		Wait:    viper.GetBool("qbit.move.wait"),
+		Timeout: viper.GetInt("qbit.move.timeout"),
+	}
+
+	if err := checkMsysPathConversion(force); err != nil {
+		fatalError(err)
+	}
+
+	// create a qbit client
+	client := qbitCreateClient()
+
+	// move
+	err := qbitMove(context.Background(), client, hash, path, opts)
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+// qbitMove moves a torrent to path, given as a path on this host
+func qbitMove(ctx context.Context, client internal.QbitClientInterface, hash string, path string, opts MoveOptions) error {
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// move
+	dest := clientPath(path)
+	if dest != path {
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, ""), "%s: pathmap: \"%s\" -> \"%s\"\n", hash, path, dest)
+	}
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, ""), "%s: requesting move to \"%s\"\n", hash, dest)
+	err = client.SetLocationCtx(ctx, []string{hash}, dest)
+	if err != nil {
+		return err
+	}
+
+	// wait for it
+	if opts.Wait {
+		return waitForMove(hash, path, time.Duration(opts.Timeout)*time.Second, func() (moveStatus, error) {
+			return qbitGetMoveStatus(ctx, client, hash)
+		})
+	}
+
+	return nil
+}
+
+func qbitGetMoveStatus(ctx context.Context, client internal.QbitClientInterface, hash string) (moveStatus, error) {
+	torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{
+		Hashes: []string{hash},
+	})
+	if err != nil {
+		return moveStatus{}, err
+	}
+	if len(torrents) != 1 {
+		return moveStatus{}, fmt.Errorf("%s: torrent not found", hash)
+	}
+	t := torrents[0]
+	status := moveStatus{
+		Moving:   t.State == qbittorrent.TorrentStateMoving,
+		SavePath: hostPath(t.SavePath),
+	}
+	if t.State == qbittorrent.TorrentStateError || t.State == qbittorrent.TorrentStateMissingFiles {
+		status.Error = string(t.State)
+	}
+
+	// the file list is only needed once the move is done
+	if !status.Moving {
+		files, err := client.GetFilesInformationCtx(ctx, hash)
+		if err != nil {
+			return moveStatus{}, err
+		}
+		for _, f := range *files {
+			status.Files = append(status.Files, f.Name)
+		}
+	}
+
+	return status, nil
+}
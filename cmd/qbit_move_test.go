@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestQbitMove_Wait(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	hash := "abc"
+	dest := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dest, "movie.mkv"), []byte("x"), 0644))
+	saved := movePollInterval
+	movePollInterval = time.Millisecond
+	t.Cleanup(func() { movePollInterval = saved })
+
+	filter := qbittorrent.TorrentFilterOptions{Hashes: []string{hash}}
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("SetLocationCtx", ctx, []string{hash}, dest).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, filter).Return([]qbittorrent.Torrent{{Hash: hash, State: qbittorrent.TorrentStateMoving, SavePath: "/old"}}, nil).Once()
+	mockClient.On("GetTorrentsCtx", ctx, filter).Return([]qbittorrent.Torrent{{Hash: hash, State: qbittorrent.TorrentStateStalledUp, SavePath: dest + "/"}}, nil).Once()
+	mockClient.On("GetFilesInformationCtx", ctx, hash).Return(&qbittorrent.TorrentFiles{{Name: "movie.mkv"}}, nil)
+
+	err := qbitMove(ctx, mockClient, hash, dest, MoveOptions{Wait: true, Timeout: 10})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestQbitMove_WaitMissingFiles(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	hash := "abc"
+	dest := t.TempDir()
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("SetLocationCtx", ctx, []string{hash}, dest).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{hash}}).Return([]qbittorrent.Torrent{{Hash: hash, State: qbittorrent.TorrentStateStalledUp, SavePath: dest}}, nil)
+	mockClient.On("GetFilesInformationCtx", ctx, hash).Return(&qbittorrent.TorrentFiles{{Name: "movie.mkv"}}, nil)
+
+	err := qbitMove(ctx, mockClient, hash, dest, MoveOptions{Wait: true, Timeout: 10})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "files missing")
+
+	mockClient.AssertExpectations(t)
+}
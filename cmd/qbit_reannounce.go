@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -16,6 +17,9 @@ import (
 	"github.com/kenstir/tortle/internal"
 )
 
+// reannounceIntervalUnit is the unit of the reannounce intervals; tests make it shorter
+var reannounceIntervalUnit = time.Second
+
 type ReannounceOptions struct {
 	Attempts      int
 	Interval      int
@@ -48,31 +52,30 @@ var qbitReannounceCmd = &cobra.Command{
 }
 
 func qbitReannounceCmdRun(cmd *cobra.Command, args []string) {
-	// get the flags
 	hash := args[0]
-	attempts := viper.GetInt("qbit.reannounce.attempts")
-	interval := viper.GetInt("qbit.reannounce.interval")
-	extraAttempts := viper.GetInt("qbit.reannounce.extra_attempts")
-	extraInterval := viper.GetInt("qbit.reannounce.extra_interval")
-	maxAge := viper.GetInt("qbit.reannounce.max_age")
 
 	// create a qbit client
 	client := qbitCreateClient()
 
 	// reannounce
-	options := ReannounceOptions{
-		Attempts:      attempts,
-		Interval:      interval,
-		ExtraAttempts: extraAttempts,
-		ExtraInterval: extraInterval,
-		MaxAge:        maxAge,
-	}
+	options := qbitReannounceOptions()
 	err := qbitReannounce(context.Background(), client, hash, options)
 	if err != nil {
 		fatalError(err)
 	}
 }
 
+// qbitReannounceOptions returns the reannounce options from flags or config
+func qbitReannounceOptions() ReannounceOptions {
+	return ReannounceOptions{
+		Attempts:      viper.GetInt("qbit.reannounce.attempts"),
+		Interval:      viper.GetInt("qbit.reannounce.interval"),
+		ExtraAttempts: viper.GetInt("qbit.reannounce.extra_attempts"),
+		ExtraInterval: viper.GetInt("qbit.reannounce.extra_interval"),
+		MaxAge:        viper.GetInt("qbit.reannounce.max_age"),
+	}
+}
+
 func qbitReannounce(ctx context.Context, client internal.QbitClientInterface, hash string, opts ReannounceOptions) error {
 
 	// connect
@@ -80,9 +83,7 @@ func qbitReannounce(ctx context.Context, client internal.QbitClientInterface, ha
 	if err != nil {
 		return err
 	}
-	if verbosity > 0 {
-		stdoutLogger.Printf("Connected to qBittorrent\n")
-	}
+	vLogf("Connected to qBittorrent\n")
 
 	// get torrent
 	torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{
@@ -98,12 +99,12 @@ func qbitReannounce(ctx context.Context, client internal.QbitClientInterface, ha
 
 	// perform startup checks
 	age := time.Now().Unix() - torrent.AddedOn
-	stdoutLogger.Printf("%s: found torrent age=%d\n", hash, age)
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, ""), "%s: found torrent age=%d\n", hash, age)
 	if age > int64(opts.MaxAge) {
 		return fmt.Errorf("torrent is %ds old, max_age is %ds", age, opts.MaxAge)
 	}
 	// if torrent.CompletionOn > 0 {
-	// 	stdoutLogger.Printf("%s: torrent is finished\n", hash)
+	// 	logf("%s: torrent is finished\n", hash)
 	// 	return
 	// }
 
@@ -128,10 +129,8 @@ func qbitReannounceUntilOK(ctx context.Context, client internal.QbitClientInterf
 		prefix := fmt.Sprintf("try %d", i)
 
 		// delay before every attempt
-		if verbosity > 0 {
-			stdoutLogger.Printf("%s: %s: sleep %d\n", hash, prefix, options.Interval)
-		}
-		time.Sleep(time.Duration(options.Interval) * time.Second)
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, prefix), "%s: %s: sleep %d\n", hash, prefix, options.Interval)
+		time.Sleep(time.Duration(options.Interval) * reannounceIntervalUnit)
 
 		// get trackers
 		trackers, err := client.GetTorrentTrackersCtx(ctx, hash)
@@ -150,7 +149,7 @@ func qbitReannounceUntilOK(ctx context.Context, client internal.QbitClientInterf
 			continue
 		}
 
-		stdoutLogger.Printf("%s: %s: torrent is OK with %d seeds\n", hash, prefix, seeds)
+		logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: torrent is OK with %d seeds\n", hash, prefix, seeds)
 		return nil
 	}
 
@@ -162,10 +161,8 @@ func qbitReannounceForGoodMeasure(ctx context.Context, client internal.QbitClien
 		prefix := fmt.Sprintf("extra %d", i)
 
 		// delay before every attempt
-		if verbosity > 0 {
-			stdoutLogger.Printf("%s: %s: sleep %d\n", hash, prefix, options.ExtraInterval)
-		}
-		time.Sleep(time.Duration(options.ExtraInterval) * time.Second)
+		logAttrsf(slog.LevelDebug, torrentAttrs(hash, prefix), "%s: %s: sleep %d\n", hash, prefix, options.ExtraInterval)
+		time.Sleep(time.Duration(options.ExtraInterval) * reannounceIntervalUnit)
 
 		// log state then reannounce
 		qbitLogTorrentProperties(ctx, client, hash, prefix)
@@ -177,19 +174,19 @@ func qbitReannounceForGoodMeasure(ctx context.Context, client internal.QbitClien
 
 func qbitForceReannounce(ctx context.Context, client internal.QbitClientInterface, hash string, prefix string) {
 	if err := client.ReAnnounceTorrentsCtx(ctx, []string{hash}); err != nil {
-		logErrorf("%s: Error reannouncing: %s\n", hash, err)
+		logAttrsf(slog.LevelError, torrentAttrs(hash, prefix), "%s: Error reannouncing: %s\n", hash, err)
 	} else {
-		logf("%s: %s: reannounce requested\n", hash, prefix)
+		logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: reannounce requested\n", hash, prefix)
 	}
 }
 
 func qbitLogTorrentProperties(ctx context.Context, client internal.QbitClientInterface, hash string, prefix string) {
 	props, err := client.GetTorrentPropertiesCtx(ctx, hash)
 	if err != nil {
-		logErrorf("%s: Error getting properties: %s\n", hash, err)
+		logAttrsf(slog.LevelError, torrentAttrs(hash, ""), "%s: Error getting properties: %s\n", hash, err)
 	}
 	duration := time.Duration(props.Reannounce) * time.Second
-	logf("%s: %s: torrent: seed=%d peer=%d pieces=%d/%d(%d%%) reannounce=%d(%s)\n", hash, prefix, props.SeedsTotal, props.PeersTotal, props.PiecesHave, props.PiecesNum, int(100*props.PiecesHave/props.PiecesNum), props.Reannounce, duration.String())
+	logAttrsf(slog.LevelInfo, torrentAttrs(hash, prefix), "%s: %s: torrent: seed=%d peer=%d pieces=%d/%d(%d%%) reannounce=%d(%s)\n", hash, prefix, props.SeedsTotal, props.PeersTotal, props.PiecesHave, props.PiecesNum, int(100*props.PiecesHave/props.PiecesNum), props.Reannounce, duration.String())
 }
 
 // Return true if a tracker is OK
@@ -211,7 +208,7 @@ func findOKTracker(trackers []qbittorrent.TorrentTracker, hash string, prefix st
 			continue
 		}
 		hostname := strings.Split(tr.Url, "/")[2]
-		logf("%s: %s: trackers[%d]: status=%s seed=%d peer=%d msg=\"%s\" u=%s\n", hash, prefix, i, trackerStatus(tr.Status), tr.NumSeeds, tr.NumPeers, tr.Message, hostname)
+		logAttrsf(slog.LevelInfo, append(torrentAttrs(hash, prefix), slog.String("tracker", hostname)), "%s: %s: trackers[%d]: status=%s seed=%d peer=%d msg=\"%s\" u=%s\n", hash, prefix, i, trackerStatus(tr.Status), tr.NumSeeds, tr.NumPeers, tr.Message, hostname)
 	}
 
 	// find the first tracker with an OK status and seeds
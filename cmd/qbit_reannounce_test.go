@@ -3,10 +3,13 @@ package cmd
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/autobrr/go-qbittorrent"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kenstir/tortle/internal"
+	"github.com/kenstir/tortle/internal/fakeqbit"
 	"github.com/kenstir/tortle/mocks"
 )
 
@@ -28,58 +31,71 @@ func TestReannounce_TorrentNotFound(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
-/*
+// newFakeQbit starts a fake qBittorrent with reannounce intervals in microseconds
+func newFakeQbit(t *testing.T) (*fakeqbit.Server, internal.QbitClientInterface) {
+	server := fakeqbit.New()
+	t.Cleanup(server.Close)
+	saved := reannounceIntervalUnit
+	reannounceIntervalUnit = time.Microsecond
+	t.Cleanup(func() { reannounceIntervalUnit = saved })
+	return server, internal.NewQbitClient(server.Config())
+}
+
 func TestReannounce(t *testing.T) {
-	mockClient := mocks.NewQbitMockClient()
+	server, client := newFakeQbit(t)
 	ctx := context.Background()
-	logger := log.New(os.Stdout, "", log.LstdFlags)
 	hash := "testhash"
-	attempts := 2
-	interval := 1
-	extraAttempts := 1
-	extraInterval := 1
-	maxAge := 60 * 60
-
-	torrent := qbittorrent.Torrent{Hash: hash}
-	trackers := []qbittorrent.TorrentTracker{
-		{Status: qbittorrent.TrackerStatusNotWorking, Url: "http://tracker1.example.org"},
-		{Status: qbittorrent.TrackerStatusOK, Url: "http://tracker2.example.org"},
+	notWorking := []qbittorrent.TorrentTracker{
+		{Status: qbittorrent.TrackerStatusNotWorking, Url: "http://tracker1.example.org/announce", Message: "unregistered torrent"},
 	}
-
-	mockClient.On("LoginCtx", ctx).Return(nil)
-	mockClient.On("GetTorrents", qbittorrent.TorrentFilterOptions{Hashes: []string{hash}}).Return([]qbittorrent.Torrent{torrent}, nil)
-	mockClient.On("GetTorrentTrackersCtx", ctx, hash).Return(trackers, nil)
-	mockClient.On("ReAnnounceTorrentsCtx", ctx, []string{hash}).Return(nil)
-
-	options := ReannounceOptions{
-		Attempts:      attempts,
-		Interval:      interval,
-		ExtraAttempts: extraAttempts,
-		ExtraInterval: extraInterval,
-		MaxAge:        maxAge,
+	ok := []qbittorrent.TorrentTracker{
+		{Status: qbittorrent.TrackerStatusOK, Url: "http://tracker1.example.org/announce", NumSeeds: 3},
 	}
-	qbitReannounce(ctx, logger, mockClient, hash, options)
+	server.AddTorrent(fakeqbit.Torrent{
+		Torrent:    qbittorrent.Torrent{Hash: hash, AddedOn: time.Now().Unix()},
+		Properties: qbittorrent.TorrentProperties{PiecesNum: 10},
+		Trackers:   [][]qbittorrent.TorrentTracker{notWorking, notWorking, ok},
+	})
 
-	mockClient.AssertExpectations(t)
+	opts := ReannounceOptions{Attempts: 5, Interval: 7, ExtraAttempts: 2, ExtraInterval: 30, MaxAge: 60 * 60}
+	err := qbitReannounce(ctx, client, hash, opts)
+	assert.NoError(t, err)
+
+	torrent, _ := server.Torrent(hash)
+	assert.Equal(t, 3, torrent.TrackerRequests)
+	assert.Equal(t, 2+2, torrent.Reannounces)
 }
-*/
 
-/*
-func TestReannounce_ErrorGettingTorrents(t *testing.T) {
-	mockClient := mocks.NewQbitMockClient()
+func TestReannounce_AttemptsExhausted(t *testing.T) {
+	server, client := newFakeQbit(t)
 	ctx := context.Background()
 	hash := "testhash"
-	attempts := 3
-	interval := 1
-	verbosity := 1
+	server.AddTorrent(fakeqbit.Torrent{
+		Torrent:    qbittorrent.Torrent{Hash: hash, AddedOn: time.Now().Unix()},
+		Properties: qbittorrent.TorrentProperties{PiecesNum: 10},
+		Trackers: [][]qbittorrent.TorrentTracker{
+			{{Status: qbittorrent.TrackerStatusNotContacted, Url: "http://tracker1.example.org/announce"}},
+		},
+	})
 
-	mockClient.On("LoginCtx", ctx).Return(nil)
-	mockClient.On("GetTorrents", qbittorrent.TorrentFilterOptions{Hashes: []string{hash}}).Return(nil, assert.AnError)
+	opts := ReannounceOptions{Attempts: 3, Interval: 7, ExtraAttempts: 2, ExtraInterval: 30, MaxAge: 60 * 60}
+	err := qbitReannounce(ctx, client, hash, opts)
+	assert.EqualError(t, err, "testhash: Reannounce attempts exhausted")
+
+	torrent, _ := server.Torrent(hash)
+	assert.Equal(t, 3, torrent.Reannounces)
+}
 
-	assert.Panics(t, func() {
-		qbitReannounce(ctx, mockClient, hash, attempts, interval, verbosity)
+func TestReannounce_TooOld(t *testing.T) {
+	server, client := newFakeQbit(t)
+	ctx := context.Background()
+	hash := "testhash"
+	server.AddTorrent(fakeqbit.Torrent{
+		Torrent: qbittorrent.Torrent{Hash: hash, AddedOn: time.Now().Add(-2 * time.Hour).Unix()},
 	})
 
-	mockClient.AssertExpectations(t)
+	err := qbitReannounce(ctx, client, hash, ReannounceOptions{Attempts: 1, MaxAge: 60 * 60})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max_age is 3600s")
+	assert.NotContains(t, server.Requests(), "torrents/reannounce")
 }
-*/
@@ -5,48 +5,289 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/autobrr/go-qbittorrent"
 	"github.com/kenstir/tortle/internal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+type RmOptions struct {
+	Filter     string
+	Rules      []RemoveRule // qbit only
+	DryRun     bool
+	KeepFiles  bool
+	Yes        bool        // don't ask for confirmation
+	PurgeLinks bool        // also purge hard-linked copies found in ScanPaths
+	ScanPaths  []string    // see purge
+	List       ListOptions // used to print torrents in dry-run mode
+}
+
 func init() {
 	qbitCmd.AddCommand(qbitRmCmd)
 
 	qbitRmCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	qbitRmCmd.Flags().StringSliceP("rule", "r", []string{}, "Find torrents using the named rules from tt.toml (or \"all\")")
+	qbitRmCmd.Flags().BoolP("dry-run", "n", false, "List the torrents that would be removed")
+	qbitRmCmd.Flags().BoolP("keep-files", "k", false, "Remove the torrents but keep their files")
+	qbitRmCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+	qbitRmCmd.Flags().Bool("purge-links", false, "Also purge hard-linked copies of the files in purge.scan-path")
+	viper.BindPFlag("qbit.rm.filter", qbitRmCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("qbit.rm.rule", qbitRmCmd.Flags().Lookup("rule"))
+	viper.BindPFlag("qbit.rm.dry-run", qbitRmCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("qbit.rm.keep-files", qbitRmCmd.Flags().Lookup("keep-files"))
+	viper.BindPFlag("qbit.rm.yes", qbitRmCmd.Flags().Lookup("yes"))
+	viper.BindPFlag("qbit.rm.purge-links", qbitRmCmd.Flags().Lookup("purge-links"))
 }
 
 var qbitRmCmd = &cobra.Command{
-	Use:     "rm hash [hash]...",
+	Use:     "rm [hash]...",
 	Aliases: []string{"remove", "del", "delete"},
 	Short:   "Remove torrents",
-	Long:    "Remove torrents from qBittorrent by their hash.",
-	Args:    cobra.MinimumNArgs(1),
-	Run:     qbitRmCmdRun,
+	Long: `Remove torrents from qBittorrent by their hash, by name, or by rule.
+
+Rules are defined in tt.toml, e.g.
+
+  [[qbit.rules]]
+  name = "cleanup"
+  trackers = ["tracker.example.org"]
+  min_ratio = 1.0
+  min_seed_time = "10d"
+  free_space_below = "500GiB"
+  exclude_tags = ["keep"]
+
+A torrent matches a rule if its tracker is on one of the trackers (when none of
+its trackers is working, any tracker in its list counts), has none of the excluded
+tags, and has reached min_ratio OR min_seed_time.  If free_space_below is given,
+the rule only applies while free space is below that amount.  Check first with:
+
+  tt qbit rm --rule cleanup --dry-run
+
+You will be asked to confirm before anything is removed; use --yes when running
+from cron.  With --purge-links, hard-linked copies of the torrent files in the
+purge.scan-path directories are removed as well (see tt purge --help).
+`,
+	Run: qbitRmCmdRun,
 }
 
 func qbitRmCmdRun(cmd *cobra.Command, args []string) {
+	// check flags
+	columns := viper.GetStringSlice("qbit.columns")
+	if err := checkColumns(columns, qbitValidColumns); err != nil {
+		fatalError(err)
+	}
+	rules, err := qbitLoadRemoveRules(viper.GetStringSlice("qbit.rm.rule"))
+	if err != nil {
+		fatalError(err)
+	}
+
 	// create a qbit client
 	client := qbitCreateClient()
 
-	err := qbitRm(context.Background(), client, args)
+	// collect options and go
+	opts := RmOptions{
+		Filter:     viper.GetString("qbit.rm.filter"),
+		Rules:      rules,
+		DryRun:     viper.GetBool("qbit.rm.dry-run"),
+		KeepFiles:  viper.GetBool("qbit.rm.keep-files"),
+		Yes:        viper.GetBool("qbit.rm.yes"),
+		PurgeLinks: viper.GetBool("qbit.rm.purge-links"),
+		ScanPaths:  viper.GetStringSlice("purge.scan-path"),
+		List: ListOptions{
+			Columns:  columns,
+			Humanize: viper.GetBool("qbit.humanize"),
+			Instance: currentInstance("qbit"),
+		},
+	}
+	err = qbitRm(context.Background(), client, args, opts)
 	if err != nil {
 		fatalError(err)
 	}
 }
 
-func qbitRm(ctx context.Context, client internal.QbitClientInterface, hashes []string) error {
+func qbitRm(ctx context.Context, client internal.QbitClientInterface, hashes []string, opts RmOptions) error {
+	// refuse to remove everything
+	if len(hashes) == 0 && opts.Filter == "" && len(opts.Rules) == 0 {
+		return fmt.Errorf("no torrents specified; give a hash, --filter, or --rule")
+	}
+	if opts.PurgeLinks && len(opts.ScanPaths) == 0 {
+		return fmt.Errorf("--purge-links requires purge.scan-path")
+	}
+
 	// connect
 	err := client.LoginCtx(ctx)
 	if err != nil {
 		return err
 	}
 
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, opts)
+	if err != nil {
+		return err
+	}
+	if len(torrents) == 0 {
+		vLogf("No torrents to remove\n")
+		return nil
+	}
+
+	// in dry-run mode, just list them
+	if opts.DryRun {
+		qbitPrintTorrents(torrents, opts.List)
+		return nil
+	}
+
+	// ask first
+	if !opts.Yes {
+		qbitPrintTorrents(torrents, ListOptions{Columns: []string{"name", "size", "ratio"}, Humanize: true})
+		if !confirm(rmConfirmPrompt(len(torrents), opts)) {
+			return fmt.Errorf("not confirmed, nothing removed")
+		}
+	}
+
+	// find hard-linked files now, since removing the torrents may delete them
+	var lastError error
+	targets := map[string]*purgeTargets{}
+	if opts.PurgeLinks {
+		for _, t := range torrents {
+			pt, err := findPurgeTargets(osPurgeFS, hostPath(t.ContentPath))
+			if err != nil {
+				logErrorf("%s: error purging links: %v\n", t.Hash, err)
+				lastError = err
+				continue
+			}
+			targets[t.Hash] = pt
+		}
+	}
+
 	// remove torrents
-	err = client.DeleteTorrentsCtx(ctx, hashes, true)
+	var selected []string
+	for _, t := range torrents {
+		selected = append(selected, t.Hash)
+	}
+	err = client.DeleteTorrentsCtx(ctx, selected, !opts.KeepFiles)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// when purging, check which torrents are really gone before touching their copies
+	remaining := map[string]bool{}
+	if opts.PurgeLinks {
+		left, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{Hashes: selected})
+		if err != nil {
+			return err
+		}
+		for _, t := range left {
+			remaining[t.Hash] = true
+		}
+	}
+	for _, t := range torrents {
+		if remaining[t.Hash] {
+			logErrorf("%s: error removing: torrent still present\n", t.Hash)
+			lastError = fmt.Errorf("%d of %d torrents could not be removed", len(remaining), len(torrents))
+			continue
+		}
+		logf("%s: removed \"%s\"\n", t.Hash, t.Name)
+	}
+
+	// purge hard-linked copies of the removed torrents
+	for _, t := range torrents {
+		pt := targets[t.Hash]
+		if pt == nil || remaining[t.Hash] {
+			continue
+		}
+		_, err = pt.purge(osPurgeFS, opts.ScanPaths, false)
+		if err != nil {
+			logErrorf("%s: error purging links: %v\n", t.Hash, err)
+			lastError = err
+		}
+	}
+
+	return lastError
+}
+
+// rmConfirmPrompt returns the question to ask before removing torrents
+func rmConfirmPrompt(n int, opts RmOptions) string {
+	what := "and their files"
+	if opts.KeepFiles {
+		what = "but keep their files"
+	}
+	if opts.PurgeLinks {
+		what += fmt.Sprintf(", purging linked copies in %v", opts.ScanPaths)
+	}
+	return fmt.Sprintf("Remove %d torrents %s?", n, what)
+}
+
+// qbitSelectTorrents returns the torrents with the given hashes (or all torrents if none are given)
+// that match the filter and any of the rules
+func qbitSelectTorrents(ctx context.Context, client internal.QbitClientInterface, hashes []string, opts RmOptions) ([]qbittorrent.Torrent, error) {
+	torrents, err := client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{
+		Sort:   "name",
+		Hashes: hashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// check that all specified torrents were found
+	if len(hashes) > 0 && len(hashes) != len(torrents) {
+		for _, hash := range hashes {
+			if !slices.ContainsFunc(torrents, func(t qbittorrent.Torrent) bool { return t.Hash == hash }) {
+				return nil, fmt.Errorf("%s: torrent not found", hash)
+			}
+		}
+	}
+
+	// get free space only if a rule needs it
+	var freeSpace int64
+	if slices.ContainsFunc(opts.Rules, func(r RemoveRule) bool { return r.needsFreeSpace() }) {
+		n, err := client.GetFreeSpaceOnDiskCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		freeSpace = int64(n)
+		vLogf("Free space: %s\n", humanizeBytes(freeSpace))
+	}
+
+	var selected []qbittorrent.Torrent
+	for _, t := range torrents {
+		if !matchesFilter(t.Name, opts.Filter) {
+			continue
+		}
+		if len(opts.Rules) > 0 {
+			var trackerURLs []string
+			if slices.ContainsFunc(opts.Rules, func(r RemoveRule) bool { return r.needsTrackers() }) {
+				trackerURLs, err = qbitTorrentTrackerURLs(ctx, client, t)
+				if err != nil {
+					return nil, err
+				}
+			}
+			i := slices.IndexFunc(opts.Rules, func(r RemoveRule) bool { return r.matches(t, trackerURLs, freeSpace) })
+			if i < 0 {
+				continue
+			}
+			vLogf("%s: matches rule %s\n", t.Hash, opts.Rules[i].Name)
+		}
+		selected = append(selected, t)
+	}
+
+	return selected, nil
+}
+
+// qbitTorrentTrackerURLs returns the torrent's working tracker, or if no tracker is
+// working (t.Tracker is empty), every tracker in its list
+func qbitTorrentTrackerURLs(ctx context.Context, client internal.QbitClientInterface, t qbittorrent.Torrent) ([]string, error) {
+	if t.Tracker != "" {
+		return []string{t.Tracker}, nil
+	}
+	trackers, err := client.GetTorrentTrackersCtx(ctx, t.Hash)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, tracker := range trackers {
+		urls = append(urls, tracker.Url)
+	}
+	return urls, nil
 }
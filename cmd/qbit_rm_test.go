@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/internal/fakeqbit"
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestRemoveRule_Matches(t *testing.T) {
+	rule := RemoveRule{
+		Name:        "tl",
+		Trackers:    []string{"example.org"},
+		MinRatio:    1.0,
+		MinSeedTime: "10d",
+		ExcludeTags: []string{"keep"},
+	}
+	assert.NoError(t, rule.validate())
+
+	day := int64(24 * 60 * 60)
+	tracker := "https://tracker.example.org/announce"
+	assert.True(t, rule.matches(qbittorrent.Torrent{Tracker: tracker, Ratio: 1.5}, []string{tracker}, 0))
+	assert.True(t, rule.matches(qbittorrent.Torrent{Tracker: tracker, Ratio: 0.1, SeedingTime: 11 * day}, []string{tracker}, 0))
+	assert.False(t, rule.matches(qbittorrent.Torrent{Tracker: tracker, Ratio: 0.1, SeedingTime: 9 * day}, []string{tracker}, 0))
+	assert.False(t, rule.matches(qbittorrent.Torrent{Tracker: tracker, Ratio: 1.5, Tags: "racing, keep"}, []string{tracker}, 0))
+	assert.False(t, rule.matches(qbittorrent.Torrent{Tracker: "https://notexample.org/announce", Ratio: 1.5}, []string{"https://notexample.org/announce"}, 0))
+	assert.False(t, rule.matches(qbittorrent.Torrent{Ratio: 1.5}, nil, 0))
+}
+
+func TestRemoveRule_FreeSpaceBelow(t *testing.T) {
+	rule := RemoveRule{Name: "full", MinRatio: 1.0, FreeSpaceBelow: "100GiB"}
+	assert.NoError(t, rule.validate())
+
+	torrent := qbittorrent.Torrent{Ratio: 2.0}
+	assert.True(t, rule.matches(torrent, nil, 50<<30))
+	assert.False(t, rule.matches(torrent, nil, 200<<30))
+}
+
+func TestRemoveRule_Invalid(t *testing.T) {
+	rule := RemoveRule{Name: "everything"}
+	assert.Error(t, rule.validate())
+
+	rule = RemoveRule{Name: "bad", MinSeedTime: "ten days"}
+	assert.Error(t, rule.validate())
+}
+
+func TestRm_NothingSpecified(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+
+	err := qbitRm(context.Background(), mockClient, nil, RmOptions{})
+	assert.Error(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRm_Rules(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	rule := RemoveRule{Name: "ratio", MinRatio: 1.0, ExcludeTags: []string{"keep"}}
+	assert.NoError(t, rule.validate())
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "A", Ratio: 1.2},
+		{Hash: "b", Name: "B", Ratio: 0.5},
+		{Hash: "c", Name: "C", Ratio: 3.0, Tags: "keep"},
+	}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("DeleteTorrentsCtx", ctx, []string{"a"}, true).Return(nil)
+
+	err := qbitRm(ctx, mockClient, nil, RmOptions{Rules: []RemoveRule{rule}, Yes: true})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRm_NotConfirmed(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	confirmInput = strings.NewReader("n\n")
+	defer func() { confirmInput = os.Stdin }()
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return([]qbittorrent.Torrent{{Hash: "a", Name: "A"}}, nil)
+
+	err := qbitRm(ctx, mockClient, []string{"a"}, RmOptions{})
+	assert.Error(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRm_KeepFiles(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	confirmInput = strings.NewReader("y\n")
+	defer func() { confirmInput = os.Stdin }()
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return([]qbittorrent.Torrent{{Hash: "a", Name: "A"}}, nil)
+	mockClient.On("DeleteTorrentsCtx", ctx, []string{"a"}, false).Return(nil)
+
+	err := qbitRm(ctx, mockClient, []string{"a"}, RmOptions{KeepFiles: true})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestQbitRmCmd_FakeServer(t *testing.T) {
+	server, _ := newFakeQbit(t)
+	server.AddTorrent(fakeqbit.Torrent{Torrent: qbittorrent.Torrent{Hash: "a", Name: "Some.Movie.2020"}})
+	server.AddTorrent(fakeqbit.Torrent{Torrent: qbittorrent.Torrent{Hash: "b", Name: "Show.S01E01"}})
+	configPath := filepath.Join(t.TempDir(), "tt.toml")
+	assert.NoError(t, os.WriteFile(configPath, nil, 0644))
+	_, stdout, _ := newTestLogSession(t)
+
+	code := runTT(t, "--config", configPath, "qbit", "--server", server.URL, "-U", fakeqbit.Username, "-P", fakeqbit.Password, "rm", "--filter", "movie", "--keep-files", "--yes")
+	assert.Equal(t, 0, code)
+
+	assert.Equal(t, map[string]bool{"a": false}, server.Deleted())
+	_, ok := server.Torrent("b")
+	assert.True(t, ok)
+	assert.Contains(t, stdout.String(), "Some.Movie.2020")
+}
+
+func TestRm_PurgeLinksAfterDelete(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	root := t.TempDir()
+	library := filepath.Join(root, "library")
+	assert.NoError(t, os.MkdirAll(library, 0755))
+	for _, name := range []string{"a.mkv", "b.mkv"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0644))
+		assert.NoError(t, os.Link(filepath.Join(root, name), filepath.Join(library, name)))
+	}
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "A", ContentPath: filepath.Join(root, "a.mkv")},
+		{Hash: "b", Name: "B", ContentPath: filepath.Join(root, "b.mkv")},
+	}
+
+	// the client deletes a's files but b stays behind
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil).Once()
+	mockClient.On("DeleteTorrentsCtx", ctx, []string{"a", "b"}, true).Return(nil).Run(func(mock.Arguments) {
+		os.Remove(filepath.Join(root, "a.mkv"))
+	})
+	mockClient.On("GetTorrentsCtx", ctx, qbittorrent.TorrentFilterOptions{Hashes: []string{"a", "b"}}).Return(torrents[1:], nil).Once()
+
+	err := qbitRm(ctx, mockClient, []string{"a", "b"}, RmOptions{Yes: true, PurgeLinks: true, ScanPaths: []string{library}})
+	assert.EqualError(t, err, "1 of 2 torrents could not be removed")
+	assert.NoFileExists(t, filepath.Join(library, "a.mkv"))
+	assert.FileExists(t, filepath.Join(library, "b.mkv"))
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRm_RuleTrackerNotWorking(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	rule := RemoveRule{Name: "tl", Trackers: []string{"example.org"}, MinRatio: 1.0}
+	assert.NoError(t, rule.validate())
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "A", Ratio: 1.2},
+		{Hash: "b", Name: "B", Ratio: 1.2, Tracker: "https://other.org/announce"},
+	}
+
+	// a has no working tracker, so its tracker list is checked instead
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("GetTorrentTrackersCtx", ctx, "a").Return([]qbittorrent.TorrentTracker{
+		{Url: "** [DHT] **"},
+		{Url: "https://tracker.example.org/announce", Status: qbittorrent.TrackerStatusNotWorking},
+	}, nil)
+
+	selected, err := qbitSelectTorrents(ctx, mockClient, nil, RmOptions{Rules: []RemoveRule{rule}})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "a", selected[0].Hash)
+
+	mockClient.AssertExpectations(t)
+}
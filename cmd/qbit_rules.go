@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/viper"
+)
+
+// RemoveRule selects torrents for removal, e.g.
+//
+//	[[qbit.rules]]
+//	name = "tl"
+//	trackers = ["tracker.example.org"]
+//	min_ratio = 1.0
+//	min_seed_time = "10d"
+//	free_space_below = "500GiB"
+//	exclude_tags = ["keep"]
+//
+// A torrent matches if its tracker is on one of the rule's trackers (or any tracker if
+// none are given; if no tracker is working, any tracker in its list counts), has none
+// of the excluded tags, and has reached either min_ratio or min_seed_time.
+// If free_space_below is set, the rule only applies when free space is below that amount.
+type RemoveRule struct {
+	Name           string   `mapstructure:"name"`
+	Trackers       []string `mapstructure:"trackers"`
+	MinRatio       float64  `mapstructure:"min_ratio"`
+	MinSeedTime    string   `mapstructure:"min_seed_time"`
+	FreeSpaceBelow string   `mapstructure:"free_space_below"`
+	ExcludeTags    []string `mapstructure:"exclude_tags"`
+
+	minSeedTime    time.Duration
+	freeSpaceBelow int64
+}
+
+// qbitLoadRemoveRules returns the rules from the config with the given names, or all of them for "all"
+func qbitLoadRemoveRules(names []string) ([]RemoveRule, error) {
+	var rules []RemoveRule
+	err := viper.UnmarshalKey("qbit.rules", &rules)
+	if err != nil {
+		return nil, fmt.Errorf("qbit.rules: %v", err)
+	}
+
+	var selected []RemoveRule
+	for _, name := range names {
+		found := false
+		for _, rule := range rules {
+			if name == "all" || rule.Name == name {
+				selected = append(selected, rule)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: rule not found in config", name)
+		}
+	}
+
+	for i := range selected {
+		err = selected[i].validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+// validate checks the rule and parses the duration and size strings
+func (r *RemoveRule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule is missing a name")
+	}
+	if r.MinRatio <= 0 && r.MinSeedTime == "" {
+		return fmt.Errorf("%s: rule needs min_ratio or min_seed_time", r.Name)
+	}
+	if r.MinSeedTime != "" {
+		d, err := parseDuration(r.MinSeedTime)
+		if err != nil {
+			return fmt.Errorf("%s: min_seed_time: %v", r.Name, err)
+		}
+		r.minSeedTime = d
+	}
+	if r.FreeSpaceBelow != "" {
+		n, err := parseBytes(r.FreeSpaceBelow)
+		if err != nil {
+			return fmt.Errorf("%s: free_space_below: %v", r.Name, err)
+		}
+		r.freeSpaceBelow = n
+	}
+	return nil
+}
+
+// needsFreeSpace returns true if the rule depends on the free space
+func (r *RemoveRule) needsFreeSpace() bool {
+	return r.freeSpaceBelow > 0
+}
+
+// needsTrackers returns true if the rule depends on the torrent's trackers
+func (r *RemoveRule) needsTrackers() bool {
+	return len(r.Trackers) > 0
+}
+
+// matches returns true if the torrent should be removed under this rule;
+// trackerURLs are the torrent's trackers, see qbitTorrentTrackerURLs
+func (r *RemoveRule) matches(t qbittorrent.Torrent, trackerURLs []string, freeSpace int64) bool {
+	if r.freeSpaceBelow > 0 && freeSpace >= r.freeSpaceBelow {
+		return false
+	}
+	if r.needsTrackers() && !slices.ContainsFunc(r.Trackers, func(tracker string) bool {
+		return slices.ContainsFunc(trackerURLs, func(u string) bool { return trackerHostMatches(u, tracker) })
+	}) {
+		return false
+	}
+	for _, tag := range r.ExcludeTags {
+		if qbitHasTag(t, tag) {
+			return false
+		}
+	}
+	if r.MinRatio > 0 && t.Ratio >= r.MinRatio {
+		return true
+	}
+	if r.minSeedTime > 0 && time.Duration(t.SeedingTime)*time.Second >= r.minSeedTime {
+		return true
+	}
+	return false
+}
+
+// trackerHostMatches returns true if trackerURL is on host or a subdomain of host
+func trackerHostMatches(trackerURL string, host string) bool {
+	u, err := url.Parse(trackerURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	hostname := strings.ToLower(u.Hostname())
+	host = strings.ToLower(host)
+	return hostname == host || strings.HasSuffix(hostname, "."+host)
+}
+
+// qbitHasTag returns true if the torrent has the tag
+func qbitHasTag(t qbittorrent.Torrent, tag string) bool {
+	return slices.Contains(qbitSplitTags(t.Tags), tag)
+}
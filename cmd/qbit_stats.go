@@ -6,14 +6,19 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/autobrr/go-qbittorrent"
 	"github.com/kenstir/tortle/internal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func init() {
 	qbitCmd.AddCommand(qbitStatsCmd)
+
+	qbitStatsCmd.Flags().String("by", "", "Also print stats for each category (\"category\")")
+	viper.BindPFlag("qbit.stats.by", qbitStatsCmd.Flags().Lookup("by"))
 }
 
 var qbitStatsCmd = &cobra.Command{
@@ -23,25 +28,39 @@ var qbitStatsCmd = &cobra.Command{
 }
 
 func qbitStatsCmdRun(cmd *cobra.Command, args []string) {
-	// create a qbit client
-	client := qbitCreateClient()
+	// check flags
+	by := viper.GetString("qbit.stats.by")
+	if by != "" && by != "category" {
+		fatalError(fmt.Errorf("unknown breakdown: %s (expected category)", by))
+	}
+
+	// tag lines with the instance only if one was asked for
+	instanceFlag := viper.GetString("qbit.instance")
+	instances, err := selectInstances("qbit", instanceFlag)
+	if err != nil {
+		fatalError(err)
+	}
 
 	// get and print stats
-	err := qbitStats(context.Background(), client)
+	err = forEachInstance(instances, func(i int, instance string) error {
+		if instanceFlag == "" {
+			instance = ""
+		}
+		return qbitStats(context.Background(), qbitCreateInstanceClient(instance), instance, by)
+	})
 	if err != nil {
 		fatalError(err)
 	}
 }
 
-func qbitStats(ctx context.Context, client internal.QbitClientInterface) error {
+// qbitStats prints stats for one instance, adding an instance tag unless instance is ""
+func qbitStats(ctx context.Context, client internal.QbitClientInterface, instance string, by string) error {
 	// connect
 	err := client.LoginCtx(ctx)
 	if err != nil {
 		return err
 	}
-	if verbosity > 0 {
-		stdoutLogger.Printf("Connected to qBittorrent\n")
-	}
+	vLogf("Connected to qBittorrent\n")
 
 	// get transfer info
 	info, err := client.GetTransferInfoCtx(ctx)
@@ -51,7 +70,7 @@ func qbitStats(ctx context.Context, client internal.QbitClientInterface) error {
 
 	// organize data into tags and fields
 	// See also https://docs.influxdata.com/influxdb/v1/write_protocols/line_protocol_tutorial/
-	host, port, err := qbitGetHostPort()
+	host, port, err := qbitGetHostPort(instance)
 	if err != nil {
 		return err
 	}
@@ -60,6 +79,9 @@ func qbitStats(ctx context.Context, client internal.QbitClientInterface) error {
 		fmt.Sprintf("client_host=%s", host),
 		fmt.Sprintf("client_port=%s", port),
 	}
+	if instance != "" {
+		tags = append(tags, fmt.Sprintf("instance=%s", escapeTagValue(instance)))
+	}
 	fields := []string{
 		fmt.Sprintf("download_rate=%d", info.DlInfoSpeed),
 		fmt.Sprintf("upload_rate=%d", info.UpInfoSpeed),
@@ -75,6 +97,24 @@ func qbitStats(ctx context.Context, client internal.QbitClientInterface) error {
 	fields = append(fields, qbitStatsAddComputedFields(torrents)...)
 
 	printMeasurement("tt_stats", tags, fields)
+
+	// add a line per category
+	if by == "category" {
+		groups := map[string][]qbittorrent.Torrent{}
+		for _, t := range torrents {
+			groups[t.Category] = append(groups[t.Category], t)
+		}
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			groupTags := append(tags[:len(tags):len(tags)], fmt.Sprintf("category=%s", escapeTagValue(name)))
+			printMeasurement("tt_stats", groupTags, qbitStatsAddComputedFields(groups[name]))
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,236 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal"
+)
+
+type TagOptions struct {
+	Auto   bool
+	Remove bool
+	Filter string
+	DryRun bool
+}
+
+func init() {
+	qbitCmd.AddCommand(qbitTagCmd)
+
+	qbitTagCmd.Flags().Bool("auto", false, "Apply tags by tracker, tracker health and hard-link status")
+	qbitTagCmd.Flags().Bool("remove", false, "Remove the tags instead of adding them")
+	qbitTagCmd.Flags().StringP("filter", "f", "", "Find torrents by name")
+	qbitTagCmd.Flags().BoolP("dry-run", "n", false, "Print the changes without making them")
+	viper.BindPFlag("qbit.tag.auto", qbitTagCmd.Flags().Lookup("auto"))
+	viper.BindPFlag("qbit.tag.remove", qbitTagCmd.Flags().Lookup("remove"))
+	viper.BindPFlag("qbit.tag.filter", qbitTagCmd.Flags().Lookup("filter"))
+	viper.BindPFlag("qbit.tag.dry-run", qbitTagCmd.Flags().Lookup("dry-run"))
+}
+
+var qbitTagCmd = &cobra.Command{
+	Use:   "tag {--auto | TAGS} [hash]...",
+	Short: "Tag torrents",
+	Long: `Add (or --remove) comma-separated TAGS on torrents selected by hash or --filter.
+
+With --auto, tag every torrent (or the given ones) by tracker and health:
+
+  site:<tracker-host>  the torrent's tracker
+  unregistered         the tracker says the torrent is not registered
+  tracker-down         the tracker is not responding
+  not-working          no tracker is working
+  noHL                 a completed torrent has no hard-linked files, e.g. not imported by Sonarr
+
+Stale auto tags are removed, so it is safe to run from cron.  If a torrent's files
+cannot be read, its noHL tag is left as it is.`,
+	Run: qbitTagCmdRun,
+}
+
+func qbitTagCmdRun(cmd *cobra.Command, args []string) {
+	opts := TagOptions{
+		Auto:   viper.GetBool("qbit.tag.auto"),
+		Remove: viper.GetBool("qbit.tag.remove"),
+		Filter: viper.GetString("qbit.tag.filter"),
+		DryRun: viper.GetBool("qbit.tag.dry-run"),
+	}
+
+	// create a qbit client
+	client := qbitCreateClient()
+
+	var err error
+	if opts.Auto {
+		err = qbitAutoTag(context.Background(), client, args, opts)
+	} else {
+		if len(args) == 0 {
+			fatalError(fmt.Errorf("no tags specified"))
+		}
+		err = qbitTag(context.Background(), client, args[0], args[1:], opts)
+	}
+	if err != nil {
+		fatalError(err)
+	}
+}
+
+// qbitTag adds or removes tags on the selected torrents
+func qbitTag(ctx context.Context, client internal.QbitClientInterface, tags string, hashes []string, opts TagOptions) error {
+	if len(hashes) == 0 && opts.Filter == "" {
+		return fmt.Errorf("no torrents specified; give a hash or --filter")
+	}
+
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, RmOptions{Filter: opts.Filter})
+	if err != nil {
+		return err
+	}
+	var selected []string
+	for _, t := range torrents {
+		selected = append(selected, t.Hash)
+		if opts.DryRun || verbosity > 0 {
+			logf("%s: %s tags \"%s\"\n", t.Hash, addOrRemove(opts.Remove), tags)
+		}
+	}
+	if len(selected) == 0 || opts.DryRun {
+		return nil
+	}
+
+	if opts.Remove {
+		return client.RemoveTagsCtx(ctx, selected, tags)
+	}
+	return client.AddTagsCtx(ctx, selected, tags)
+}
+
+// qbitAutoTag applies the auto tags to all torrents, or the ones with the given hashes
+func qbitAutoTag(ctx context.Context, client internal.QbitClientInterface, hashes []string, opts TagOptions) error {
+	// connect
+	err := client.LoginCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// find torrents
+	torrents, err := qbitSelectTorrents(ctx, client, hashes, RmOptions{Filter: opts.Filter})
+	if err != nil {
+		return err
+	}
+	vLogf("Found %d torrents\n", len(torrents))
+
+	// work out the changes, grouped by tag so we make one request per tag
+	toAdd := map[string][]string{}
+	toRemove := map[string][]string{}
+	for _, t := range torrents {
+		trackers, err := client.GetTorrentTrackersCtx(ctx, t.Hash)
+		if err != nil {
+			logErrorf("%s: Error getting trackers: %v\n", t.Hash, err)
+			continue
+		}
+		desired := qbitAutoTags(t, trackers)
+		add, remove := tagDiff(qbitSplitTags(t.Tags), desired)
+		for _, tag := range add {
+			toAdd[tag] = append(toAdd[tag], t.Hash)
+		}
+		for _, tag := range remove {
+			toRemove[tag] = append(toRemove[tag], t.Hash)
+		}
+		if len(add) > 0 || len(remove) > 0 {
+			logf("%s: add=%v remove=%v \"%s\"\n", t.Hash, add, remove, t.Name)
+		}
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	// apply them
+	for _, tag := range sortedKeys(toRemove) {
+		err = client.RemoveTagsCtx(ctx, toRemove[tag], tag)
+		if err != nil {
+			return err
+		}
+	}
+	for _, tag := range sortedKeys(toAdd) {
+		err = client.AddTagsCtx(ctx, toAdd[tag], tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// qbitAutoTags returns the auto tags that should be on the torrent
+func qbitAutoTags(t qbittorrent.Torrent, trackers []qbittorrent.TorrentTracker) []string {
+	var tags []string
+	anyOK := false
+	anyNotWorking := false
+	health := ""
+	for _, tr := range trackers {
+		if tr.Status == qbittorrent.TrackerStatusDisabled {
+			continue
+		}
+		// skip DHT, PeX and LSD, which say nothing about the torrent's trackers
+		u, err := url.Parse(tr.Url)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		site := tagSitePrefix + u.Hostname()
+		if !slices.Contains(tags, site) {
+			tags = append(tags, site)
+		}
+		switch tr.Status {
+		case qbittorrent.TrackerStatusOK:
+			anyOK = true
+		case qbittorrent.TrackerStatusNotWorking:
+			anyNotWorking = true
+			if c := classifyTrackerMessage(tr.Message); c == tagUnregistered || health == "" {
+				health = c
+			}
+		}
+	}
+	if !anyOK && anyNotWorking {
+		tags = append(tags, tagNotWorking)
+		if health != "" {
+			tags = append(tags, health)
+		}
+	}
+
+	// only completed torrents are expected to be hard-linked
+	if t.Progress >= 1 && t.ContentPath != "" {
+		linked, err := hasHardLinks(hostPath(t.ContentPath))
+		if err != nil {
+			// keep the current tag rather than flap while the path is unreadable
+			vLogf("%s: %v\n", t.Hash, err)
+			if qbitHasTag(t, tagNoHL) {
+				tags = append(tags, tagNoHL)
+			}
+		} else if !linked {
+			tags = append(tags, tagNoHL)
+		}
+	}
+
+	return tags
+}
+
+// qbitSplitTags splits the comma-separated tags string
+func qbitSplitTags(tags string) []string {
+	var result []string
+	for _, s := range strings.Split(tags, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
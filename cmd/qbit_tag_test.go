@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/autobrr/go-qbittorrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kenstir/tortle/mocks"
+)
+
+func TestClassifyTrackerMessage(t *testing.T) {
+	assert.Equal(t, tagUnregistered, classifyTrackerMessage("Unregistered torrent"))
+	assert.Equal(t, tagUnregistered, classifyTrackerMessage("Torrent not found"))
+	assert.Equal(t, tagTrackerDown, classifyTrackerMessage("Connection timed out"))
+	assert.Equal(t, "", classifyTrackerMessage(""))
+	assert.Equal(t, "", classifyTrackerMessage("Too many requests"))
+}
+
+func TestQbitAutoTags(t *testing.T) {
+	torrent := qbittorrent.Torrent{Hash: "a"}
+	trackers := []qbittorrent.TorrentTracker{
+		{Url: "** [DHT] **", Status: qbittorrent.TrackerStatusDisabled},
+		{Url: "https://tracker.example.org/announce", Status: qbittorrent.TrackerStatusNotWorking, Message: "Unregistered torrent"},
+	}
+	assert.Equal(t, []string{"site:tracker.example.org", tagNotWorking, tagUnregistered}, qbitAutoTags(torrent, trackers))
+
+	trackers[1].Status = qbittorrent.TrackerStatusOK
+	assert.Equal(t, []string{"site:tracker.example.org"}, qbitAutoTags(torrent, trackers))
+
+	// a working DHT does not make the tracker healthy
+	trackers[0].Status = qbittorrent.TrackerStatusOK
+	trackers[1].Status = qbittorrent.TrackerStatusNotWorking
+	assert.Equal(t, []string{"site:tracker.example.org", tagNotWorking, tagUnregistered}, qbitAutoTags(torrent, trackers))
+}
+
+func TestAutoTags_KeepNoHLWhenStatFails(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	torrent := qbittorrent.Torrent{Hash: "a", Progress: 1, ContentPath: missing, Tags: tagNoHL}
+	assert.Equal(t, []string{tagNoHL}, qbitAutoTags(torrent, nil))
+	torrent.Tags = ""
+	assert.Empty(t, qbitAutoTags(torrent, nil))
+
+	ts := &deluge.TorrentStatus{Hash: "a", Name: "missing", SavePath: filepath.Dir(missing), IsFinished: true}
+	assert.Equal(t, "nohl", delugeAutoLabel(ts, "nohl"))
+	assert.Equal(t, "", delugeAutoLabel(ts, ""))
+}
+
+func TestTagDiff(t *testing.T) {
+	add, remove := tagDiff([]string{"keep", "site:old.org", tagNotWorking}, []string{"site:new.org", tagNotWorking})
+	assert.Equal(t, []string{"site:new.org"}, add)
+	assert.Equal(t, []string{"site:old.org"}, remove)
+}
+
+func TestQbitAutoTag_Idempotent(t *testing.T) {
+	mockClient := mocks.NewQbitMockClient()
+	ctx := context.Background()
+	torrents := []qbittorrent.Torrent{
+		{Hash: "a", Name: "A", Tags: "site:tracker.example.org, racing"},
+		{Hash: "b", Name: "B", Tags: "unregistered, not-working"},
+	}
+	trackers := []qbittorrent.TorrentTracker{
+		{Url: "https://tracker.example.org/announce", Status: qbittorrent.TrackerStatusOK},
+	}
+
+	mockClient.On("LoginCtx", ctx).Return(nil)
+	mockClient.On("GetTorrentsCtx", ctx, mock.Anything).Return(torrents, nil)
+	mockClient.On("GetTorrentTrackersCtx", ctx, mock.Anything).Return(trackers, nil)
+	mockClient.On("RemoveTagsCtx", ctx, []string{"b"}, tagNotWorking).Return(nil)
+	mockClient.On("RemoveTagsCtx", ctx, []string{"b"}, tagUnregistered).Return(nil)
+	mockClient.On("AddTagsCtx", ctx, []string{"b"}, "site:tracker.example.org").Return(nil)
+
+	err := qbitAutoTag(ctx, mockClient, nil, TagOptions{Auto: true})
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
@@ -5,17 +5,17 @@ package cmd
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
-var logFile io.WriteCloser
 var verbosity int
+var flagKeys []string
 
 type VersionInfo struct {
 	Version string `json:"version"`
@@ -52,9 +52,17 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./tt.toml)")
 
 	rootCmd.PersistentFlags().StringP("log-file", "l", "", "Log file (default is stdout)")
+	rootCmd.PersistentFlags().String("log-format", "text", "Log format {text, json}")
+	rootCmd.PersistentFlags().Int("log-max-size", 0, "Rotate the log file when it reaches this many MB (default no limit)")
+	rootCmd.PersistentFlags().Duration("log-max-age", 0, "Rotate the log file when it is this old, e.g. 168h (default no limit)")
+	rootCmd.PersistentFlags().Int("log-max-backups", 5, "Number of rotated log files to keep, 0 to keep all")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress all output except errors")
 	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Increase verbosity (may be specified multiple times)")
 	viper.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
+	viper.BindPFlag("log-format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("log-max-size", rootCmd.PersistentFlags().Lookup("log-max-size"))
+	viper.BindPFlag("log-max-age", rootCmd.PersistentFlags().Lookup("log-max-age"))
+	viper.BindPFlag("log-max-backups", rootCmd.PersistentFlags().Lookup("log-max-backups"))
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	// fmt.Println("root init")
@@ -63,6 +71,10 @@ func init() {
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	// fmt.Println("root initConfig")
+
+	// remember the keys bound to flags before the config file adds its own, for tt doctor
+	flagKeys = viper.AllKeys()
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -86,49 +98,56 @@ func initConfig() {
 		}
 	}
 
-	// Read in environment variables that match flags
-	// On second thought, don't; it causes $USERNAME to override the config file
-	// viper.AutomaticEnv()
+	initEnv()
+}
+
+// initEnv reads environment variables that match config keys, with a TT_ prefix
+// so that e.g. $USERNAME does not override the config file, e.g.
+// TT_QBIT_PASSWORD for qbit.password, or TT_QBIT_SEEDBOX1_PASSWORD for qbit.seedbox1.password
+func initEnv() {
+	viper.SetEnvPrefix("TT")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
 }
 
 func initLogging() {
 	//fmt.Println("root initLogging")
 
-	// handle quiet first
-	if viper.GetBool("quiet") {
-		stdoutLogger.SetOutput(io.Discard)
-		return
+	err := logs.start(LogOptions{
+		File:      viper.GetString("log-file"),
+		Format:    viper.GetString("log-format"),
+		Quiet:     viper.GetBool("quiet"),
+		Verbosity: verbosity,
+		Rotate:    logRotateOptions(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
 	}
+}
 
-	// open the log file
-	logFileName := viper.GetString("log-file")
-	if logFileName != "" {
-		logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: %v\n", logFileName, err)
-			os.Exit(1)
-		}
-		stdoutLogger.SetOutput(logFile)
-		stderrLogger.SetOutput(logFile)
+// logRotateOptions returns the log rotation options from flags or config
+func logRotateOptions() LogRotateOptions {
+	return LogRotateOptions{
+		MaxSize:    int64(viper.GetInt("log-max-size")) * 1024 * 1024,
+		MaxAge:     viper.GetDuration("log-max-age"),
+		MaxBackups: viper.GetInt("log-max-backups"),
 	}
 }
 
 func finalizeLogging() {
 	//fmt.Println("root finalizeLogging")
 
-	if logFile != nil {
-		logFile.Close()
-	}
+	logs.finish()
 }
 
 // exitWithFinalizers closes the log file and exits with the given code
 func exitWithFinalizers(code int) {
-	finalizeLogging()
-	os.Exit(code)
+	logs.exitWith(code)
 }
 
 // fatalError closes the log file, prints the error message to stderr, and exits 1
 func fatalError(err error) {
-	stderrLogger.Printf("%v\n", err)
+	logErrorf("%v\n", err)
 	exitWithFinalizers(1)
 }
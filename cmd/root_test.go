@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// runTT runs tt with args end to end and returns the exit code; logs must be a test
+// log session.  Flags are reset afterwards, since cobra keeps them between runs.
+func runTT(t *testing.T, args ...string) int {
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		resetFlags(rootCmd)
+		cfgFile = ""
+	})
+	rootCmd.SetArgs(args)
+	code := catchExit(func() {
+		if err := rootCmd.Execute(); err != nil {
+			logs.exitWith(1)
+		}
+	})
+	if code < 0 {
+		code = 0
+	}
+	return code
+}
+
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			// Set would append to the slice, so replace it with the default, e.g. "[all]"
+			var def []string
+			if f.DefValue != "[]" {
+				def = strings.Split(strings.Trim(f.DefValue, "[]"), ",")
+			}
+			sv.Replace(def)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+func TestResetFlags_RestoresSliceDefaults(t *testing.T) {
+	flags := moverCmd.Flags()
+	assert.NoError(t, flags.Set("rule", "tv"))
+	scanPaths := purgeCmd.Flags()
+	assert.NoError(t, scanPaths.Set("scan-path", "/library"))
+
+	resetFlags(rootCmd)
+
+	rules, _ := flags.GetStringSlice("rule")
+	assert.Equal(t, []string{"all"}, rules)
+	paths, _ := scanPaths.GetStringSlice("scan-path")
+	assert.Empty(t, paths)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// tags applied by tag --auto
+const (
+	tagSitePrefix   = "site:"
+	tagUnregistered = "unregistered"
+	tagTrackerDown  = "tracker-down"
+	tagNotWorking   = "not-working"
+	tagNoHL         = "noHL"
+)
+
+// tracker messages meaning the torrent was deleted or never existed on the tracker
+var unregisteredMessages = []string{
+	"unregistered",
+	"not registered",
+	"torrent not found",
+	"torrent does not exist",
+	"unknown torrent",
+	"infohash not found",
+	"info hash not found",
+	"trumped",
+	"nuked",
+	"dupe",
+}
+
+// tracker messages meaning the tracker itself is having trouble
+var trackerDownMessages = []string{
+	"timed out",
+	"timeout",
+	"connection refused",
+	"bad gateway",
+	"service unavailable",
+	"gateway time-out",
+	"tracker is down",
+	"maintenance",
+	"end of file",
+	"host not found",
+	"502",
+	"503",
+	"504",
+}
+
+// classifyTrackerMessage returns tagUnregistered or tagTrackerDown if the tracker message matches, else ""
+func classifyTrackerMessage(msg string) string {
+	msg = strings.ToLower(msg)
+	if msg == "" {
+		return ""
+	}
+	for _, s := range unregisteredMessages {
+		if strings.Contains(msg, s) {
+			return tagUnregistered
+		}
+	}
+	for _, s := range trackerDownMessages {
+		if strings.Contains(msg, s) {
+			return tagTrackerDown
+		}
+	}
+	return ""
+}
+
+// isAutoTag returns true if tag is one that tag --auto manages
+func isAutoTag(tag string) bool {
+	return strings.HasPrefix(tag, tagSitePrefix) || slices.Contains([]string{tagUnregistered, tagTrackerDown, tagNotWorking, tagNoHL}, tag)
+}
+
+// hasHardLinks returns true if any file in path has more than one link
+func hasHardLinks(path string) (bool, error) {
+	n, err := countHardLinkedFiles(osPurgeFS, path)
+	return n > 0, err
+}
+
+// tagDiff returns the auto tags to add and remove to get from current to desired
+func tagDiff(current []string, desired []string) (add []string, remove []string) {
+	for _, tag := range desired {
+		if !slices.Contains(current, tag) {
+			add = append(add, tag)
+		}
+	}
+	for _, tag := range current {
+		if isAutoTag(tag) && !slices.Contains(desired, tag) {
+			remove = append(remove, tag)
+		}
+	}
+	return add, remove
+}
+
+func addOrRemove(remove bool) string {
+	if remove {
+		return "remove"
+	}
+	return "add"
+}
+
+// sortedKeys returns the keys of m in order, so requests are made in a stable order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,340 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kenstir/tortle/internal/bencode"
+)
+
+// torrentMetainfo is what tt shows about a .torrent file
+type torrentMetainfo struct {
+	Hash         string        `json:"hash,omitempty"`    // v1 info-hash, empty for v2-only torrents
+	HashV2       string        `json:"hash_v2,omitempty"` // v2 info-hash, empty for v1-only torrents
+	Name         string        `json:"name"`
+	PieceLength  int64         `json:"piece_length"`
+	Size         int64         `json:"size"`
+	Files        []torrentFile `json:"files"`
+	Trackers     []string      `json:"trackers"`
+	Private      bool          `json:"private"`
+	Source       string        `json:"source,omitempty"`
+	Comment      string        `json:"comment,omitempty"`
+	CreatedBy    string        `json:"created_by,omitempty"`
+	CreationDate int64         `json:"creation_date,omitempty"`
+}
+
+type torrentFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+func init() {
+	rootCmd.AddCommand(torrentCmd)
+	torrentCmd.AddCommand(torrentInfoCmd)
+
+	torrentInfoCmd.Flags().StringSliceP("columns", "c", []string{"hash", "size", "name"}, "Columns to display")
+	torrentInfoCmd.Flags().Bool("files", false, "List the files in each torrent instead")
+	torrentInfoCmd.Flags().Bool("json", false, "Print everything as JSON")
+	torrentInfoCmd.Flags().Bool("humanize", true, "Humanize sizes, e.g. \"2.1 GiB\"")
+	torrentInfoCmd.Flags().BoolP("noheader", "n", false, "Don't print the header line")
+	viper.BindPFlag("torrent.columns", torrentInfoCmd.Flags().Lookup("columns"))
+	viper.BindPFlag("torrent.files", torrentInfoCmd.Flags().Lookup("files"))
+	viper.BindPFlag("torrent.json", torrentInfoCmd.Flags().Lookup("json"))
+	viper.BindPFlag("torrent.humanize", torrentInfoCmd.Flags().Lookup("humanize"))
+	viper.BindPFlag("torrent.noheader", torrentInfoCmd.Flags().Lookup("noheader"))
+}
+
+var torrentValidColumns = []string{
+	"comment",
+	"created_by",
+	"creation_date",
+	"files",
+	"hash",
+	"hash_v2",
+	"name",
+	"piece_length",
+	"private",
+	"size",
+	"source",
+	"trackers",
+}
+
+var torrentCmd = &cobra.Command{
+	Use:     "torrent",
+	Aliases: []string{"t"},
+	Short:   "Inspect .torrent files",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var torrentInfoCmd = &cobra.Command{
+	Use:   "info FILE.torrent...",
+	Short: "Show the metainfo of .torrent files",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   torrentInfoCmdRun,
+}
+
+func torrentInfoCmdRun(cmd *cobra.Command, args []string) {
+	// check flags
+	columns := viper.GetStringSlice("torrent.columns")
+	if err := checkColumns(columns, torrentValidColumns); err != nil {
+		fatalError(err)
+	}
+	opts := ListOptions{
+		Columns:  columns,
+		NoHeader: viper.GetBool("torrent.noheader"),
+		Humanize: viper.GetBool("torrent.humanize"),
+	}
+
+	// parse all files first so the output is all or nothing
+	var infos []torrentMetainfo
+	for _, arg := range args {
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			fatalError(err)
+		}
+		info, err := parseMetainfo(data)
+		if err != nil {
+			fatalError(fmt.Errorf("%s: %v", arg, err))
+		}
+		infos = append(infos, info)
+	}
+
+	switch {
+	case viper.GetBool("torrent.json"):
+		for _, info := range infos {
+			jsonOutput, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				fatalError(err)
+			}
+			fmt.Println(string(jsonOutput))
+		}
+	case viper.GetBool("torrent.files"):
+		torrentPrintFiles(infos, opts)
+	default:
+		torrentPrintInfos(infos, opts)
+	}
+}
+
+// torrentPrintInfos prints one CSV line per torrent
+func torrentPrintInfos(infos []torrentMetainfo, opts ListOptions) {
+	if !opts.NoHeader {
+		fmt.Printf("%s\n", strings.Join(opts.Columns, ","))
+	}
+	for _, info := range infos {
+		var line []string
+		for _, column := range opts.Columns {
+			line = append(line, torrentFormatColumn(column, info, opts.Humanize))
+		}
+		fmt.Printf("%s\n", strings.Join(line, ","))
+	}
+}
+
+// torrentPrintFiles prints one CSV line per file
+func torrentPrintFiles(infos []torrentMetainfo, opts ListOptions) {
+	if !opts.NoHeader {
+		fmt.Printf("hash,size,path\n")
+	}
+	for _, info := range infos {
+		hash := info.Hash
+		if hash == "" {
+			hash = info.HashV2
+		}
+		for _, f := range info.Files {
+			size := fmt.Sprintf("%d", f.Size)
+			if opts.Humanize {
+				size = humanizeBytes(f.Size)
+			}
+			fmt.Printf("%s,%s,%s\n", hash, size, f.Path)
+		}
+	}
+}
+
+// format the given column
+func torrentFormatColumn(column string, info torrentMetainfo, humanize bool) string {
+	switch column {
+	case "comment":
+		return info.Comment
+	case "created_by":
+		return info.CreatedBy
+	case "creation_date":
+		return formatTimestamp(info.CreationDate)
+	case "files":
+		return fmt.Sprintf("%d", len(info.Files))
+	case "hash":
+		return info.Hash
+	case "hash_v2":
+		return info.HashV2
+	case "name":
+		return info.Name
+	case "piece_length":
+		if humanize {
+			return humanizeBytes(info.PieceLength)
+		}
+		return fmt.Sprintf("%d", info.PieceLength)
+	case "private":
+		return fmt.Sprintf("%v", info.Private)
+	case "size":
+		if humanize {
+			return humanizeBytes(info.Size)
+		}
+		return fmt.Sprintf("%d", info.Size)
+	case "source":
+		return info.Source
+	case "trackers":
+		return strings.Join(info.Trackers, " ")
+	default:
+		return fmt.Sprintf("Unknown column: %s", column)
+	}
+}
+
+// parseMetainfo decodes the contents of a v1, v2 or hybrid .torrent file
+func parseMetainfo(data []byte) (torrentMetainfo, error) {
+	var mi torrentMetainfo
+	v, err := bencode.Decode(data)
+	if err != nil {
+		return mi, err
+	}
+	root, ok := v.(map[string]any)
+	if !ok {
+		return mi, fmt.Errorf("not a .torrent file")
+	}
+	info, ok := root["info"].(map[string]any)
+	if !ok {
+		return mi, fmt.Errorf("missing info dictionary")
+	}
+	rawInfo, err := bencode.RawDictValue(data, "info")
+	if err != nil {
+		return mi, err
+	}
+
+	// info-hashes; v1 torrents have pieces, v2 torrents have meta version 2
+	if _, ok := info["pieces"]; ok {
+		mi.Hash, err = torrentInfoHash(data)
+		if err != nil {
+			return mi, err
+		}
+	}
+	if dictInt(info, "meta version") == 2 {
+		sum := sha256.Sum256(rawInfo)
+		mi.HashV2 = hex.EncodeToString(sum[:])
+	}
+	if mi.Hash == "" && mi.HashV2 == "" {
+		return mi, fmt.Errorf("info dictionary has neither pieces nor meta version 2")
+	}
+
+	mi.Name = dictString(info, "name")
+	mi.PieceLength = dictInt(info, "piece length")
+	mi.Private = dictInt(info, "private") == 1
+	mi.Source = dictString(info, "source")
+	mi.Comment = dictString(root, "comment")
+	mi.CreatedBy = dictString(root, "created by")
+	mi.CreationDate = dictInt(root, "creation date")
+	mi.Trackers = metainfoTrackers(root)
+	mi.Files = metainfoFiles(info)
+	for _, f := range mi.Files {
+		mi.Size += f.Size
+	}
+	return mi, nil
+}
+
+// metainfoTrackers returns the announce URLs, from announce-list if present or else announce
+func metainfoTrackers(root map[string]any) []string {
+	trackers := []string{}
+	if tiers, ok := root["announce-list"].([]any); ok {
+		for _, tier := range tiers {
+			urls, _ := tier.([]any)
+			for _, u := range urls {
+				if s, ok := u.(string); ok && s != "" {
+					trackers = append(trackers, s)
+				}
+			}
+		}
+	}
+	if len(trackers) == 0 {
+		if s := dictString(root, "announce"); s != "" {
+			trackers = append(trackers, s)
+		}
+	}
+	return trackers
+}
+
+// metainfoFiles returns the files in a torrent, from the v1 file list or the v2 file tree.
+// Paths are relative to the save path, so multi-file torrents start with the torrent name.
+func metainfoFiles(info map[string]any) []torrentFile {
+	name := dictString(info, "name")
+	files := []torrentFile{}
+
+	// v1 single file
+	if _, ok := info["length"]; ok {
+		return append(files, torrentFile{Path: name, Size: dictInt(info, "length")})
+	}
+
+	// v1 multi file, skipping BEP 47 padding files
+	if list, ok := info["files"].([]any); ok {
+		for _, item := range list {
+			f, _ := item.(map[string]any)
+			if strings.Contains(dictString(f, "attr"), "p") {
+				continue
+			}
+			parts := []string{name}
+			pathList, _ := f["path"].([]any)
+			for _, p := range pathList {
+				s, _ := p.(string)
+				parts = append(parts, s)
+			}
+			files = append(files, torrentFile{Path: path.Join(parts...), Size: dictInt(f, "length")})
+		}
+		return files
+	}
+
+	// v2 file tree; a single-file torrent has the file at the top level
+	if tree, ok := info["file tree"].(map[string]any); ok {
+		walkFileTree(tree, "", &files)
+		if len(files) > 1 || (len(files) == 1 && files[0].Path != name) {
+			for i := range files {
+				files[i].Path = path.Join(name, files[i].Path)
+			}
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	}
+	return files
+}
+
+// walkFileTree collects the files of a v2 file tree, where a file is a node with an "" key
+func walkFileTree(node map[string]any, prefix string, files *[]torrentFile) {
+	for key, value := range node {
+		child, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		if leaf, ok := child[""].(map[string]any); ok {
+			*files = append(*files, torrentFile{Path: path.Join(prefix, key), Size: dictInt(leaf, "length")})
+			continue
+		}
+		walkFileTree(child, path.Join(prefix, key), files)
+	}
+}
+
+func dictString(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func dictInt(m map[string]any, key string) int64 {
+	n, _ := m[key].(int64)
+	return n
+}
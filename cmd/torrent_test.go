@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetainfo_SingleFile(t *testing.T) {
+	mi, err := parseMetainfo(testTorrent)
+	assert.NoError(t, err)
+	assert.Equal(t, "8694d6007ae15e276cbda435c410f6e2b6bd6f76", mi.Hash)
+	assert.Equal(t, "", mi.HashV2)
+	assert.Equal(t, "a.txt", mi.Name)
+	assert.Equal(t, int64(16384), mi.PieceLength)
+	assert.Equal(t, []torrentFile{{Path: "a.txt", Size: 5}}, mi.Files)
+	assert.Equal(t, []string{"http://tracker/announce"}, mi.Trackers)
+	assert.False(t, mi.Private)
+}
+
+func TestParseMetainfo_MultiFile(t *testing.T) {
+	data := []byte("d8:announce1:x13:announce-listll2:t1el2:t2ee4:infod5:filesl" +
+		"d6:lengthi3e4:pathl3:sub5:b.txteed4:attr1:p6:lengthi7e4:pathl4:.pad1:7eed6:lengthi4e4:pathl5:c.txtee" +
+		"e4:name3:dir12:piece lengthi16384e6:pieces20:012345678901234567897:privatei1e6:source3:SRCee")
+	mi, err := parseMetainfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []torrentFile{{Path: "dir/sub/b.txt", Size: 3}, {Path: "dir/c.txt", Size: 4}}, mi.Files)
+	assert.Equal(t, int64(7), mi.Size)
+	assert.Equal(t, []string{"t1", "t2"}, mi.Trackers)
+	assert.True(t, mi.Private)
+	assert.Equal(t, "SRC", mi.Source)
+}
+
+func TestParseMetainfo_V2(t *testing.T) {
+	data := []byte("d4:infod9:file treed5:a.txtd0:d6:lengthi5e11:pieces root32:0123456789012345678901234567890" +
+		"1ee5:b.txtd0:d6:lengthi6eeee12:meta versioni2e4:name3:dir12:piece lengthi16384eee")
+	mi, err := parseMetainfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "", mi.Hash)
+	assert.Len(t, mi.HashV2, 64)
+	assert.Equal(t, []torrentFile{{Path: "dir/a.txt", Size: 5}, {Path: "dir/b.txt", Size: 6}}, mi.Files)
+	assert.Equal(t, int64(11), mi.Size)
+}
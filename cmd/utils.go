@@ -4,11 +4,28 @@ Copyright © 2025 Kenneth H. Cox
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// confirmInput is where confirm reads the answer; replaced in tests
+var confirmInput io.Reader = os.Stdin
+
+// confirm prints the prompt to stderr and returns true if the answer is yes
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // convert a unix timestamp to a string for output
 func formatTimestamp(timestamp int64) string {
 	t := time.Unix(timestamp, 0)
@@ -16,6 +33,21 @@ func formatTimestamp(timestamp int64) string {
 	return t.Format("2006-01-02 15:04:05")
 }
 
+// checkColumns returns an error if any column is not in validColumns
+func checkColumns(columns []string, validColumns []string) error {
+	for _, column := range columns {
+		if !slices.Contains(validColumns, column) {
+			return fmt.Errorf("unknown column: %s (expected one of {%s})", column, strings.Join(validColumns, ", "))
+		}
+	}
+	return nil
+}
+
+// matchesFilter returns true if name contains filter, ignoring case
+func matchesFilter(name string, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
 // print a line of data in InfluxDB line protocol format
 func printMeasurement(measurement string, tags []string, fields []string) {
 	timestamp := time.Now().UnixNano()
@@ -27,28 +59,32 @@ func printMeasurement(measurement string, tags []string, fields []string) {
 	)
 }
 
-// logf logs a message to the stdoutLogger
+// escapeTagValue escapes a tag value for InfluxDB line protocol; empty values become "none"
+func escapeTagValue(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(value)
+}
+
+// logf logs a message at info level
 func logf(format string, args ...interface{}) {
-	stdoutLogger.Printf(format, args...)
+	logAttrsf(slog.LevelInfo, nil, format, args...)
 }
 
-// logErrorf logs a message to the stderrLogger
+// logErrorf logs a message at error level, to stderr unless there is a log file
 func logErrorf(format string, args ...interface{}) {
-	stderrLogger.Printf(format, args...)
+	logAttrsf(slog.LevelError, nil, format, args...)
 }
 
-// vLogf logs a message if verbosity is greater than 0
+// vLogf logs a message at debug level, enabled with -v
 func vLogf(format string, args ...interface{}) {
-	if verbosity > 0 {
-		stdoutLogger.Printf(format, args...)
-	}
+	logAttrsf(slog.LevelDebug, nil, format, args...)
 }
 
-// vvLogf logs a message if verbosity is greater than 1
+// vvLogf logs a message at trace level, enabled with -vv
 func vvLogf(format string, args ...interface{}) {
-	if verbosity > 1 {
-		stdoutLogger.Printf(format, args...)
-	}
+	logAttrsf(LevelTrace, nil, format, args...)
 }
 
 // humanizeBytes converts a byte count to a human-readable string with appropriate units
@@ -78,3 +114,63 @@ func humanizeBytes(bytes int64) string {
 		return fmt.Sprintf("%d B", bytes)
 	}
 }
+
+// parseDuration is like time.ParseDuration but also accepts days and weeks, e.g. "10d" or "2w3d"
+func parseDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	rest := strings.TrimSpace(s)
+	for _, unit := range []struct {
+		suffix string
+		d      time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		if i := strings.Index(rest, unit.suffix); i >= 0 {
+			n, err := strconv.ParseFloat(rest[:i], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %s", s)
+			}
+			total += time.Duration(n * float64(unit.d))
+			rest = rest[i+1:]
+		}
+	}
+	if rest == "" {
+		if total == 0 && strings.TrimSpace(s) == "" {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		return total, nil
+	}
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return total + d, nil
+}
+
+// parseBytes converts a size like "500GiB", "1.5 TB" or "1024" to a byte count.
+// Decimal and binary suffixes are both treated as binary, to match humanizeBytes.
+func parseBytes(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	str = strings.TrimSuffix(strings.TrimSuffix(str, "B"), "I")
+	multiplier := int64(1)
+	if str != "" {
+		switch str[len(str)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		case 'P':
+			multiplier = 1 << 50
+		}
+		if multiplier > 1 {
+			str = str[:len(str)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
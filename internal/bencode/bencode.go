@@ -0,0 +1,144 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+
+// Package bencode decodes the bencode format used by .torrent files.
+//
+// Values decode to string (byte strings, which may hold binary data), int64,
+// []any and map[string]any.
+package bencode
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Decode decodes a single bencoded value that must span all of data
+func Decode(data []byte) (any, error) {
+	v, end, err := decodeValue(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if end != len(data) {
+		return nil, fmt.Errorf("bencode: trailing data at offset %d", end)
+	}
+	return v, nil
+}
+
+// RawDictValue returns the undecoded bytes of the value stored under key in the
+// top-level dictionary, e.g. the "info" dictionary needed to compute an info-hash
+func RawDictValue(data []byte, key string) ([]byte, error) {
+	if len(data) == 0 || data[0] != 'd' {
+		return nil, fmt.Errorf("bencode: not a dictionary")
+	}
+	pos := 1
+	for pos < len(data) && data[pos] != 'e' {
+		k, end, err := decodeString(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		_, valueEnd, err := decodeValue(data, end)
+		if err != nil {
+			return nil, err
+		}
+		if k == key {
+			return data[end:valueEnd], nil
+		}
+		pos = valueEnd
+	}
+	return nil, fmt.Errorf("bencode: key %q not found", key)
+}
+
+func decodeValue(data []byte, pos int) (any, int, error) {
+	if pos >= len(data) {
+		return nil, pos, fmt.Errorf("bencode: unexpected end of data")
+	}
+	switch c := data[pos]; {
+	case c == 'i':
+		return decodeInt(data, pos)
+	case c == 'l':
+		return decodeList(data, pos)
+	case c == 'd':
+		return decodeDict(data, pos)
+	case c >= '0' && c <= '9':
+		return decodeString(data, pos)
+	default:
+		return nil, pos, fmt.Errorf("bencode: invalid byte %q at offset %d", c, pos)
+	}
+}
+
+func decodeInt(data []byte, pos int) (any, int, error) {
+	end := indexByte(data, pos+1, 'e')
+	if end < 0 {
+		return nil, pos, fmt.Errorf("bencode: unterminated integer at offset %d", pos)
+	}
+	n, err := strconv.ParseInt(string(data[pos+1:end]), 10, 64)
+	if err != nil {
+		return nil, pos, fmt.Errorf("bencode: invalid integer at offset %d", pos)
+	}
+	return n, end + 1, nil
+}
+
+func decodeString(data []byte, pos int) (string, int, error) {
+	colon := indexByte(data, pos, ':')
+	if colon < 0 {
+		return "", pos, fmt.Errorf("bencode: invalid string at offset %d", pos)
+	}
+	n, err := strconv.Atoi(string(data[pos:colon]))
+	if err != nil || n < 0 {
+		return "", pos, fmt.Errorf("bencode: invalid string length at offset %d", pos)
+	}
+	// compare before adding, so a huge length cannot overflow
+	if n > len(data)-colon-1 {
+		return "", pos, fmt.Errorf("bencode: string at offset %d overruns data", pos)
+	}
+	end := colon + 1 + n
+	return string(data[colon+1 : end]), end, nil
+}
+
+func decodeList(data []byte, pos int) (any, int, error) {
+	list := []any{}
+	pos++
+	for pos < len(data) && data[pos] != 'e' {
+		v, end, err := decodeValue(data, pos)
+		if err != nil {
+			return nil, pos, err
+		}
+		list = append(list, v)
+		pos = end
+	}
+	if pos >= len(data) {
+		return nil, pos, fmt.Errorf("bencode: unterminated list")
+	}
+	return list, pos + 1, nil
+}
+
+func decodeDict(data []byte, pos int) (any, int, error) {
+	dict := map[string]any{}
+	pos++
+	for pos < len(data) && data[pos] != 'e' {
+		k, end, err := decodeString(data, pos)
+		if err != nil {
+			return nil, pos, err
+		}
+		v, end, err := decodeValue(data, end)
+		if err != nil {
+			return nil, pos, err
+		}
+		dict[k] = v
+		pos = end
+	}
+	if pos >= len(data) {
+		return nil, pos, fmt.Errorf("bencode: unterminated dictionary")
+	}
+	return dict, pos + 1, nil
+}
+
+func indexByte(data []byte, pos int, c byte) int {
+	for i := pos; i < len(data); i++ {
+		if data[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,52 @@
+package bencode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	v, err := Decode([]byte("d4:listl3:abci-7ee3:numi42ee"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"list": []any{"abc", int64(-7)}, "num": int64(42)}, v)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	tests := []string{
+		"",
+		"9223372036854775807:abc",
+		"4294967295:abc",
+		"99999999999999999999:abc",
+		"-1:abc",
+		"5:abc",
+		"3abc",
+		"i12",
+		"ixe",
+		"l3:abc",
+		"d3:abc",
+		"di1ei2ee",
+		"x",
+		"3:abcextra",
+	}
+	for _, input := range tests {
+		assert.NotPanics(t, func() {
+			_, err := Decode([]byte(input))
+			assert.Error(t, err, "input %q", input)
+		})
+	}
+}
+
+func TestRawDictValue(t *testing.T) {
+	data := []byte("d8:announce3:url4:infod6:lengthi5eee")
+	raw, err := RawDictValue(data, "info")
+	assert.NoError(t, err)
+	assert.Equal(t, "d6:lengthi5ee", string(raw))
+
+	_, err = RawDictValue(data, "missing")
+	assert.Error(t, err)
+	_, err = RawDictValue([]byte("d9223372036854775807:abc"), "info")
+	assert.Error(t, err)
+	_, err = RawDictValue([]byte("l4:infoe"), "info")
+	assert.Error(t, err)
+}
@@ -0,0 +1,413 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+
+// Package fakedeluge is an in-process Deluge daemon for tests.
+//
+// It speaks the Deluge 2 RPC protocol the way go-deluge does: TLS with a
+// self-signed certificate, then for each call a 5-byte header (protocol
+// version and length) followed by zlib-compressed rencode.  It implements
+// daemon.login, daemon.info, core.get_torrents_status, core.force_reannounce,
+// core.move_storage and core.get_session_status.
+package fakedeluge
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/gdm85/go-rencode"
+)
+
+const (
+	Username = "localclient"
+	Password = "secret"
+	Version  = "2.1.1"
+
+	protocolVersion = 1
+	rpcResponse     = 1
+	rpcError        = 2
+	authLevelAdmin  = 10
+)
+
+// Server is a fake Deluge daemon
+type Server struct {
+	// SessionStatus is returned by core.get_session_status
+	SessionStatus deluge.SessionStatus
+
+	// OnReannounce is called after the nth reannounce of a torrent, counting from 1,
+	// to script how its tracker status changes
+	OnReannounce func(ts *deluge.TorrentStatus, n int)
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	conns    map[net.Conn]bool
+	closed   bool
+
+	mu          sync.Mutex
+	torrents    map[string]*deluge.TorrentStatus
+	reannounces map[string]int
+	calls       []string
+}
+
+// New starts a server listening on localhost; close it with Close
+func New() (*Server, error) {
+	cert, err := selfSignedCert()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener:    listener,
+		conns:       map[net.Conn]bool{},
+		torrents:    map[string]*deluge.TorrentStatus{},
+		reannounces: map[string]int{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server, closing connections the client left open, e.g. after a failed login
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Settings returns the client settings to connect to the server
+func (s *Server) Settings() deluge.Settings {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return deluge.Settings{Hostname: addr.IP.String(), Port: uint(addr.Port), Login: Username, Password: Password}
+}
+
+// AddTorrent adds or replaces a torrent
+func (s *Server) AddTorrent(ts deluge.TorrentStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.torrents[ts.Hash] = &ts
+}
+
+// Torrent returns a copy of the torrent with hash
+func (s *Server) Torrent(hash string) (deluge.TorrentStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts, ok := s.torrents[hash]
+	if !ok {
+		return deluge.TorrentStatus{}, false
+	}
+	return *ts, true
+}
+
+// Reannounces returns how many times the torrent with hash was reannounced
+func (s *Server) Reannounces(hash string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reannounces[hash]
+}
+
+// Calls returns the RPC methods called so far, e.g. "core.move_storage"
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+			conn.Close()
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// serveConn handles calls until the client closes the connection
+func (s *Server) serveConn(conn net.Conn) {
+	loggedIn := false
+	for {
+		serial, method, args, err := readRequest(conn)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.calls = append(s.calls, method)
+		var result any
+		if method == "daemon.login" {
+			result, err = login(args)
+			loggedIn = err == nil
+		} else if !loggedIn {
+			err = rpcErr{"NotAuthorizedError", "Not authorized"}
+		} else {
+			result, err = s.call(method, args)
+		}
+		s.mu.Unlock()
+		if err := writeResponse(conn, serial, result, err); err != nil {
+			return
+		}
+	}
+}
+
+// rpcErr is an exception raised by the daemon
+type rpcErr struct {
+	Type    string
+	Message string
+}
+
+func (e rpcErr) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+func login(args []any) (any, error) {
+	if len(args) != 2 || str(args[0]) != Username || str(args[1]) != Password {
+		return nil, rpcErr{"BadLoginError", "Password does not match"}
+	}
+	return int64(authLevelAdmin), nil
+}
+
+func (s *Server) call(method string, args []any) (any, error) {
+	switch method {
+	case "daemon.info":
+		return Version, nil
+	case "core.get_torrents_status":
+		return s.torrentsStatus(args)
+	case "core.force_reannounce":
+		for _, hash := range strList(arg(args, 0)) {
+			s.reannounces[hash]++
+			if ts, ok := s.torrents[hash]; ok && s.OnReannounce != nil {
+				s.OnReannounce(ts, s.reannounces[hash])
+			}
+		}
+		return nil, nil
+	case "core.move_storage":
+		dest := str(arg(args, 1))
+		for _, hash := range strList(arg(args, 0)) {
+			if ts, ok := s.torrents[hash]; ok {
+				ts.SavePath = dest
+				ts.DownloadLocation = dest
+			}
+		}
+		return nil, nil
+	case "core.get_session_status":
+		return toDict(reflect.ValueOf(s.SessionStatus)), nil
+	}
+	return nil, rpcErr{"AttributeError", fmt.Sprintf("RPC method %s not found", method)}
+}
+
+// torrentsStatus filters by the "id" and "state" keys of the filter dict, and returns
+// every field rather than just the requested keys
+func (s *Server) torrentsStatus(args []any) (any, error) {
+	var ids []string
+	var state string
+	if filter, ok := arg(args, 0).(rencode.Dictionary); ok {
+		m, err := filter.Zip()
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := m["id"]; ok {
+			ids = strList(v)
+		}
+		state = str(m["state"])
+	}
+
+	hashes := make([]string, 0, len(s.torrents))
+	for hash := range s.torrents {
+		hashes = append(hashes, hash)
+	}
+	slices.Sort(hashes)
+
+	var result rencode.Dictionary
+	for _, hash := range hashes {
+		ts := s.torrents[hash]
+		if ids != nil && !slices.Contains(ids, hash) {
+			continue
+		}
+		if state != "" && ts.State != state {
+			continue
+		}
+		result.Add(hash, toDict(reflect.ValueOf(*ts)))
+	}
+	return result, nil
+}
+
+// toDict encodes a struct the way rencode.Dictionary.ToStruct decodes it
+func toDict(v reflect.Value) rencode.Dictionary {
+	var d rencode.Dictionary
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		d.Add(rencode.ToSnakeCase(t.Field(i).Name), toValue(v.Field(i)))
+	}
+	return d
+}
+
+func toValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		return toDict(v)
+	case reflect.Slice:
+		var l rencode.List
+		for i := 0; i < v.Len(); i++ {
+			l.Add(toValue(v.Index(i)))
+		}
+		return l
+	}
+	return v.Interface()
+}
+
+func arg(args []any, i int) any {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+func str(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	}
+	return ""
+}
+
+func strList(v any) []string {
+	l, ok := v.(rencode.List)
+	if !ok {
+		return nil
+	}
+	result := []string{}
+	for _, item := range l.Values() {
+		result = append(result, str(item))
+	}
+	return result
+}
+
+// readRequest reads one call, sent as [[serial, method, args, kwargs]]
+func readRequest(r io.Reader) (int64, string, []any, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, "", nil, err
+	}
+	if header[0] != protocolVersion {
+		return 0, "", nil, fmt.Errorf("unknown protocol version %d", header[0])
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, "", nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	var calls rencode.List
+	if err := rencode.NewDecoder(zr).Scan(&calls); err != nil {
+		return 0, "", nil, err
+	}
+	if calls.Length() != 1 {
+		return 0, "", nil, errors.New("expected one call per request")
+	}
+	call, ok := calls.Values()[0].(rencode.List)
+	if !ok {
+		return 0, "", nil, errors.New("expected call as list")
+	}
+	var serial int64
+	var method string
+	var args rencode.List
+	if err := call.Scan(&serial, &method, &args); err != nil {
+		return 0, "", nil, err
+	}
+	return serial, method, args.Values(), nil
+}
+
+// writeResponse writes the result, or the exception if err is not nil
+func writeResponse(w io.Writer, serial int64, result any, err error) error {
+	var msg rencode.List
+	if err != nil {
+		e, ok := err.(rpcErr)
+		if !ok {
+			e = rpcErr{"Exception", err.Error()}
+		}
+		msg = rencode.NewList(int64(rpcError), serial, e.Type, rencode.NewList(e.Message), rencode.Dictionary{}, "Traceback (fakedeluge)")
+	} else {
+		msg = rencode.NewList(int64(rpcResponse), serial, result)
+	}
+
+	var body bytes.Buffer
+	zw := zlib.NewWriter(&body)
+	enc := rencode.NewEncoder(zw)
+	if err := enc.Encode(msg); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	// write the header and body at once, since the client reads the header with a single Read
+	out := make([]byte, 5, 5+body.Len())
+	out[0] = protocolVersion
+	binary.BigEndian.PutUint32(out[1:], uint32(body.Len()))
+	out = append(out, body.Bytes()...)
+	_, err = w.Write(out)
+	return err
+}
+
+func selfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
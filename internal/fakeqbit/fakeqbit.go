@@ -0,0 +1,248 @@
+/*
+Copyright © 2025 Kenneth H. Cox
+*/
+
+// Package fakeqbit is an in-process qBittorrent WebUI server for tests.
+//
+// It implements just enough of the v2 Web API for tt: login, torrents/info,
+// torrents/trackers, torrents/properties, torrents/reannounce and
+// torrents/delete, plus the app and Web API versions.  Tracker status is
+// scripted per torrent as a timeline that advances on every trackers request,
+// so a test can say e.g. "not working twice, then OK".
+package fakeqbit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+const (
+	Username   = "admin"
+	Password   = "adminadmin"
+	AppVersion = "v5.0.4"
+	APIVersion = "2.11.2"
+
+	sessionCookie = "SID"
+	sessionID     = "fakeqbit"
+)
+
+// Torrent is a torrent known to the server
+type Torrent struct {
+	qbittorrent.Torrent
+	Properties qbittorrent.TorrentProperties
+
+	// Trackers is the timeline of tracker lists; the nth trackers request returns
+	// Trackers[n], and the last entry repeats forever
+	Trackers [][]qbittorrent.TorrentTracker
+
+	// counted by the server
+	TrackerRequests int
+	Reannounces     int
+}
+
+// Server is a fake qBittorrent WebUI
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	torrents map[string]*Torrent
+	order    []string
+	deleted  map[string]bool // hash -> deleteFiles
+	requests []string
+}
+
+// New starts a server; close it with Close
+func New() *Server {
+	s := &Server{
+		torrents: map[string]*Torrent{},
+		deleted:  map[string]bool{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/auth/login", s.login)
+	mux.HandleFunc("GET /api/v2/app/version", s.authed(s.appVersion))
+	mux.HandleFunc("GET /api/v2/app/webapiVersion", s.authed(s.webAPIVersion))
+	mux.HandleFunc("GET /api/v2/torrents/info", s.authed(s.torrentsInfo))
+	mux.HandleFunc("GET /api/v2/torrents/trackers", s.authed(s.torrentTrackers))
+	mux.HandleFunc("GET /api/v2/torrents/properties", s.authed(s.torrentProperties))
+	mux.HandleFunc("POST /api/v2/torrents/reannounce", s.authed(s.reannounce))
+	mux.HandleFunc("POST /api/v2/torrents/delete", s.authed(s.delete))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config returns the client config to connect to the server
+func (s *Server) Config() qbittorrent.Config {
+	return qbittorrent.Config{Host: s.URL, Username: Username, Password: Password}
+}
+
+// AddTorrent adds or replaces a torrent
+func (s *Server) AddTorrent(t Torrent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.torrents[t.Hash]; !ok {
+		s.order = append(s.order, t.Hash)
+	}
+	s.torrents[t.Hash] = &t
+}
+
+// Torrent returns a copy of the torrent with hash, with the request counts
+func (s *Server) Torrent(hash string) (Torrent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.torrents[hash]
+	if !ok {
+		return Torrent{}, false
+	}
+	return *t, true
+}
+
+// Deleted returns the hashes of deleted torrents and whether their files were deleted too
+func (s *Server) Deleted() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := map[string]bool{}
+	for hash, deleteFiles := range s.deleted {
+		deleted[hash] = deleteFiles
+	}
+	return deleted
+}
+
+// Requests returns the API endpoints requested so far, e.g. "torrents/info"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	s.record(r)
+	if r.FormValue("username") != Username || r.FormValue("password") != Password {
+		w.Write([]byte("Fails."))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: sessionID, Path: "/"})
+	w.Write([]byte("Ok."))
+}
+
+// authed returns 403 for requests without the session cookie, like qBittorrent
+func (s *Server) authed(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+		if c, err := r.Cookie(sessionCookie); err != nil || c.Value != sessionID {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	}
+}
+
+func (s *Server) record(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, strings.TrimPrefix(r.URL.Path, "/api/v2/"))
+}
+
+func (s *Server) appVersion(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(AppVersion))
+}
+
+func (s *Server) webAPIVersion(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(APIVersion))
+}
+
+func (s *Server) torrentsInfo(w http.ResponseWriter, r *http.Request) {
+	var hashes []string
+	if v := r.FormValue("hashes"); v != "" {
+		hashes = strings.Split(v, "|")
+	}
+	category, hasCategory := r.Form["category"]
+	tag := r.FormValue("tag")
+
+	torrents := []qbittorrent.Torrent{}
+	for _, hash := range s.order {
+		t := s.torrents[hash]
+		if hashes != nil && !slices.Contains(hashes, hash) {
+			continue
+		}
+		if hasCategory && t.Category != category[0] {
+			continue
+		}
+		if tag != "" && !slices.Contains(strings.Split(t.Tags, ", "), tag) {
+			continue
+		}
+		torrents = append(torrents, t.Torrent)
+	}
+	writeJSON(w, torrents)
+}
+
+func (s *Server) torrentTrackers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.find(w, r.FormValue("hash"))
+	if !ok {
+		return
+	}
+	trackers := []qbittorrent.TorrentTracker{}
+	if n := len(t.Trackers); n > 0 {
+		trackers = t.Trackers[min(t.TrackerRequests, n-1)]
+	}
+	t.TrackerRequests++
+	writeJSON(w, trackers)
+}
+
+func (s *Server) torrentProperties(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.find(w, r.FormValue("hash"))
+	if !ok {
+		return
+	}
+	writeJSON(w, t.Properties)
+}
+
+func (s *Server) reannounce(w http.ResponseWriter, r *http.Request) {
+	for _, hash := range s.hashes(r) {
+		if t, ok := s.torrents[hash]; ok {
+			t.Reannounces++
+		}
+	}
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	deleteFiles := r.FormValue("deleteFiles") == "true"
+	for _, hash := range s.hashes(r) {
+		if _, ok := s.torrents[hash]; !ok {
+			continue
+		}
+		delete(s.torrents, hash)
+		s.order = slices.DeleteFunc(s.order, func(h string) bool { return h == hash })
+		s.deleted[hash] = deleteFiles
+	}
+}
+
+// hashes returns the hashes parameter, where "all" means every torrent
+func (s *Server) hashes(r *http.Request) []string {
+	v := r.FormValue("hashes")
+	if v == "all" {
+		return slices.Clone(s.order)
+	}
+	return strings.Split(v, "|")
+}
+
+// find returns the torrent with hash, or writes 404 like qBittorrent
+func (s *Server) find(w http.ResponseWriter, hash string) (*Torrent, bool) {
+	t, ok := s.torrents[hash]
+	if !ok {
+		http.Error(w, "Torrent hash was not found", http.StatusNotFound)
+	}
+	return t, ok
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -12,12 +12,29 @@ import (
 
 type QbitClientInterface interface {
 	LoginCtx(context.Context) error
+	AddTorrentFromMemoryCtx(context.Context, []byte, map[string]string) error
+	AddTorrentFromUrlCtx(context.Context, string, map[string]string) error
+	AddTagsCtx(context.Context, []string, string) error
+	CreateCategoryCtx(context.Context, string, string) error
 	DeleteTorrentsCtx(context.Context, []string, bool) error
+	ExportTorrentCtx(context.Context, string) ([]byte, error)
+	GetAppVersionCtx(context.Context) (string, error)
 	GetTransferInfoCtx(ctx context.Context) (*qbittorrent.TransferInfo, error)
+	GetCategoriesCtx(context.Context) (map[string]qbittorrent.Category, error)
+	GetFreeSpaceOnDiskCtx(context.Context) (uint64, error)
+	GetFilesInformationCtx(context.Context, string) (*qbittorrent.TorrentFiles, error)
 	GetTorrentsCtx(context.Context, qbittorrent.TorrentFilterOptions) ([]qbittorrent.Torrent, error)
 	GetTorrentTrackersCtx(context.Context, string) ([]qbittorrent.TorrentTracker, error)
 	GetTorrentPropertiesCtx(context.Context, string) (qbittorrent.TorrentProperties, error)
+	GetWebAPIVersionCtx(context.Context) (string, error)
+	PauseCtx(context.Context, []string) error
 	ReAnnounceTorrentsCtx(context.Context, []string) error
+	RecheckCtx(context.Context, []string) error
+	RemoveTagsCtx(context.Context, []string, string) error
+	ResumeCtx(context.Context, []string) error
+	SetCategoryCtx(context.Context, []string, string) error
+	SetForceStartCtx(context.Context, []string, bool) error
+	SetLocationCtx(context.Context, []string, string) error
 }
 
 type QbitClient struct {
@@ -31,14 +48,50 @@ func NewQbitClient(cfg qbittorrent.Config) *QbitClient {
 	}
 }
 
+func (qc *QbitClient) AddTorrentFromMemoryCtx(ctx context.Context, buf []byte, options map[string]string) error {
+	return qc.client.AddTorrentFromMemoryCtx(ctx, buf, options)
+}
+
+func (qc *QbitClient) AddTorrentFromUrlCtx(ctx context.Context, url string, options map[string]string) error {
+	return qc.client.AddTorrentFromUrlCtx(ctx, url, options)
+}
+
+func (qc *QbitClient) AddTagsCtx(ctx context.Context, hashes []string, tags string) error {
+	return qc.client.AddTagsCtx(ctx, hashes, tags)
+}
+
+func (qc *QbitClient) CreateCategoryCtx(ctx context.Context, category string, path string) error {
+	return qc.client.CreateCategoryCtx(ctx, category, path)
+}
+
 func (qc *QbitClient) DeleteTorrentsCtx(ctx context.Context, hashes []string, deleteFiles bool) error {
 	return qc.client.DeleteTorrentsCtx(ctx, hashes, deleteFiles)
 }
 
+func (qc *QbitClient) ExportTorrentCtx(ctx context.Context, hash string) ([]byte, error) {
+	return qc.client.ExportTorrentCtx(ctx, hash)
+}
+
+func (qc *QbitClient) GetAppVersionCtx(ctx context.Context) (string, error) {
+	return qc.client.GetAppVersionCtx(ctx)
+}
+
 func (qc *QbitClient) GetTransferInfoCtx(ctx context.Context) (*qbittorrent.TransferInfo, error) {
 	return qc.client.GetTransferInfoCtx(ctx)
 }
 
+func (qc *QbitClient) GetCategoriesCtx(ctx context.Context) (map[string]qbittorrent.Category, error) {
+	return qc.client.GetCategoriesCtx(ctx)
+}
+
+func (qc *QbitClient) GetFilesInformationCtx(ctx context.Context, hash string) (*qbittorrent.TorrentFiles, error) {
+	return qc.client.GetFilesInformationCtx(ctx, hash)
+}
+
+func (qc *QbitClient) GetFreeSpaceOnDiskCtx(ctx context.Context) (uint64, error) {
+	return qc.client.GetFreeSpaceOnDiskCtx(ctx)
+}
+
 func (qc *QbitClient) GetTorrentsCtx(ctx context.Context, filter qbittorrent.TorrentFilterOptions) ([]qbittorrent.Torrent, error) {
 	return qc.client.GetTorrentsCtx(ctx, filter)
 }
@@ -51,10 +104,42 @@ func (qc *QbitClient) GetTorrentPropertiesCtx(ctx context.Context, hash string)
 	return qc.client.GetTorrentPropertiesCtx(ctx, hash)
 }
 
+func (qc *QbitClient) GetWebAPIVersionCtx(ctx context.Context) (string, error) {
+	return qc.client.GetWebAPIVersionCtx(ctx)
+}
+
 func (qc *QbitClient) LoginCtx(ctx context.Context) error {
 	return qc.client.LoginCtx(ctx)
 }
 
+func (qc *QbitClient) PauseCtx(ctx context.Context, hashes []string) error {
+	return qc.client.PauseCtx(ctx, hashes)
+}
+
 func (qc *QbitClient) ReAnnounceTorrentsCtx(ctx context.Context, hashes []string) error {
 	return qc.client.ReAnnounceTorrentsCtx(ctx, hashes)
 }
+
+func (qc *QbitClient) RecheckCtx(ctx context.Context, hashes []string) error {
+	return qc.client.RecheckCtx(ctx, hashes)
+}
+
+func (qc *QbitClient) RemoveTagsCtx(ctx context.Context, hashes []string, tags string) error {
+	return qc.client.RemoveTagsCtx(ctx, hashes, tags)
+}
+
+func (qc *QbitClient) ResumeCtx(ctx context.Context, hashes []string) error {
+	return qc.client.ResumeCtx(ctx, hashes)
+}
+
+func (qc *QbitClient) SetCategoryCtx(ctx context.Context, hashes []string, category string) error {
+	return qc.client.SetCategoryCtx(ctx, hashes, category)
+}
+
+func (qc *QbitClient) SetForceStartCtx(ctx context.Context, hashes []string, value bool) error {
+	return qc.client.SetForceStartCtx(ctx, hashes, value)
+}
+
+func (qc *QbitClient) SetLocationCtx(ctx context.Context, hashes []string, location string) error {
+	return qc.client.SetLocationCtx(ctx, hashes, location)
+}
@@ -0,0 +1,159 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/autobrr/go-deluge"
+	"github.com/stretchr/testify/mock"
+)
+
+// DelugeMockClient is a mocked object implementing deluge.DelugeClient
+type DelugeMockClient struct {
+	mock.Mock
+}
+
+var _ deluge.DelugeClient = &DelugeMockClient{}
+
+func NewDelugeMockClient() *DelugeMockClient {
+	return &DelugeMockClient{}
+}
+
+func (_m *DelugeMockClient) Connect(ctx context.Context) error {
+	args := _m.Called(ctx)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) Close() error {
+	args := _m.Called()
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) DaemonLogin(ctx context.Context) error {
+	args := _m.Called(ctx)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) MethodsList(ctx context.Context) ([]string, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (_m *DelugeMockClient) DaemonVersion(ctx context.Context) (string, error) {
+	args := _m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) GetFreeSpace(ctx context.Context, path string) (int64, error) {
+	args := _m.Called(ctx, path)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (_m *DelugeMockClient) GetLibtorrentVersion(ctx context.Context) (string, error) {
+	args := _m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) AddTorrentMagnet(ctx context.Context, magnetURI string, options *deluge.Options) (string, error) {
+	args := _m.Called(ctx, magnetURI, options)
+	return args.String(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) AddTorrentURL(ctx context.Context, url string, options *deluge.Options) (string, error) {
+	args := _m.Called(ctx, url, options)
+	return args.String(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) AddTorrentFile(ctx context.Context, fileName, fileContentBase64 string, options *deluge.Options) (string, error) {
+	args := _m.Called(ctx, fileName, fileContentBase64, options)
+	return args.String(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) RemoveTorrents(ctx context.Context, ids []string, rmFiles bool) ([]deluge.TorrentError, error) {
+	args := _m.Called(ctx, ids, rmFiles)
+	return args.Get(0).([]deluge.TorrentError), args.Error(1)
+}
+
+func (_m *DelugeMockClient) RemoveTorrent(ctx context.Context, id string, rmFiles bool) (bool, error) {
+	args := _m.Called(ctx, id, rmFiles)
+	return args.Bool(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) PauseTorrents(ctx context.Context, ids ...string) error {
+	args := _m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) ResumeTorrents(ctx context.Context, ids ...string) error {
+	args := _m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) TorrentsStatus(ctx context.Context, state deluge.TorrentState, ids []string) (map[string]*deluge.TorrentStatus, error) {
+	args := _m.Called(ctx, state, ids)
+	return args.Get(0).(map[string]*deluge.TorrentStatus), args.Error(1)
+}
+
+func (_m *DelugeMockClient) TorrentStatus(ctx context.Context, id string) (*deluge.TorrentStatus, error) {
+	args := _m.Called(ctx, id)
+	return args.Get(0).(*deluge.TorrentStatus), args.Error(1)
+}
+
+func (_m *DelugeMockClient) MoveStorage(ctx context.Context, torrentIDs []string, dest string) error {
+	args := _m.Called(ctx, torrentIDs, dest)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) SetTorrentTracker(ctx context.Context, id, tracker string) error {
+	args := _m.Called(ctx, id, tracker)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) SetTorrentOptions(ctx context.Context, id string, options *deluge.Options) error {
+	args := _m.Called(ctx, id, options)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) SessionState(ctx context.Context) ([]string, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (_m *DelugeMockClient) ForceReannounce(ctx context.Context, ids []string) error {
+	args := _m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) GetAvailablePlugins(ctx context.Context) ([]string, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (_m *DelugeMockClient) GetEnabledPlugins(ctx context.Context) ([]string, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (_m *DelugeMockClient) EnablePlugin(ctx context.Context, name string) error {
+	args := _m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) DisablePlugin(ctx context.Context, name string) error {
+	args := _m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (_m *DelugeMockClient) TestListenPort(ctx context.Context) (bool, error) {
+	args := _m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (_m *DelugeMockClient) GetListenPort(ctx context.Context) (uint16, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).(uint16), args.Error(1)
+}
+
+func (_m *DelugeMockClient) GetSessionStatus(ctx context.Context) (*deluge.SessionStatus, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).(*deluge.SessionStatus), args.Error(1)
+}
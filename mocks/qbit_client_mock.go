@@ -16,16 +16,61 @@ func NewQbitMockClient() *QbitMockClient {
 	return &QbitMockClient{}
 }
 
+func (_m *QbitMockClient) AddTorrentFromMemoryCtx(ctx context.Context, buf []byte, options map[string]string) error {
+	args := _m.Called(ctx, buf, options)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) AddTorrentFromUrlCtx(ctx context.Context, url string, options map[string]string) error {
+	args := _m.Called(ctx, url, options)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) AddTagsCtx(ctx context.Context, hashes []string, tags string) error {
+	args := _m.Called(ctx, hashes, tags)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) CreateCategoryCtx(ctx context.Context, category string, path string) error {
+	args := _m.Called(ctx, category, path)
+	return args.Error(0)
+}
+
 func (_m *QbitMockClient) DeleteTorrentsCtx(ctx context.Context, hashes []string, deleteFiles bool) error {
 	args := _m.Called(ctx, hashes, deleteFiles)
 	return args.Error(0)
 }
 
+func (_m *QbitMockClient) ExportTorrentCtx(ctx context.Context, hash string) ([]byte, error) {
+	args := _m.Called(ctx, hash)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (_m *QbitMockClient) GetAppVersionCtx(ctx context.Context) (string, error) {
+	args := _m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
 func (_m *QbitMockClient) GetTransferInfoCtx(ctx context.Context) (*qbittorrent.TransferInfo, error) {
 	args := _m.Called(ctx)
 	return args.Get(0).(*qbittorrent.TransferInfo), args.Error(1)
 }
 
+func (_m *QbitMockClient) GetCategoriesCtx(ctx context.Context) (map[string]qbittorrent.Category, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).(map[string]qbittorrent.Category), args.Error(1)
+}
+
+func (_m *QbitMockClient) GetFilesInformationCtx(ctx context.Context, hash string) (*qbittorrent.TorrentFiles, error) {
+	args := _m.Called(ctx, hash)
+	return args.Get(0).(*qbittorrent.TorrentFiles), args.Error(1)
+}
+
+func (_m *QbitMockClient) GetFreeSpaceOnDiskCtx(ctx context.Context) (uint64, error) {
+	args := _m.Called(ctx)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 func (_m *QbitMockClient) GetTorrentsCtx(ctx context.Context, filter qbittorrent.TorrentFilterOptions) ([]qbittorrent.Torrent, error) {
 	args := _m.Called(ctx, filter)
 	return args.Get(0).([]qbittorrent.Torrent), args.Error(1)
@@ -41,12 +86,52 @@ func (_m *QbitMockClient) GetTorrentPropertiesCtx(ctx context.Context, hash stri
 	return args.Get(0).(qbittorrent.TorrentProperties), args.Error(1)
 }
 
+func (_m *QbitMockClient) GetWebAPIVersionCtx(ctx context.Context) (string, error) {
+	args := _m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
 func (_m *QbitMockClient) LoginCtx(ctx context.Context) error {
 	args := _m.Called(ctx)
 	return args.Error(0)
 }
 
+func (_m *QbitMockClient) PauseCtx(ctx context.Context, hashes []string) error {
+	args := _m.Called(ctx, hashes)
+	return args.Error(0)
+}
+
 func (_m *QbitMockClient) ReAnnounceTorrentsCtx(ctx context.Context, hashes []string) error {
 	args := _m.Called(ctx, hashes)
 	return args.Error(0)
 }
+
+func (_m *QbitMockClient) RecheckCtx(ctx context.Context, hashes []string) error {
+	args := _m.Called(ctx, hashes)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) RemoveTagsCtx(ctx context.Context, hashes []string, tags string) error {
+	args := _m.Called(ctx, hashes, tags)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) ResumeCtx(ctx context.Context, hashes []string) error {
+	args := _m.Called(ctx, hashes)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) SetCategoryCtx(ctx context.Context, hashes []string, category string) error {
+	args := _m.Called(ctx, hashes, category)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) SetForceStartCtx(ctx context.Context, hashes []string, value bool) error {
+	args := _m.Called(ctx, hashes, value)
+	return args.Error(0)
+}
+
+func (_m *QbitMockClient) SetLocationCtx(ctx context.Context, hashes []string, location string) error {
+	args := _m.Called(ctx, hashes, location)
+	return args.Error(0)
+}